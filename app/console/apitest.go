@@ -0,0 +1,133 @@
+package console
+
+import (
+	"flag"
+	"fmt"
+	"gomen/config"
+	"gomen/database/migrations"
+	"gomen/database/seeders"
+	"gomen/helpers"
+	"gomen/internal/postman"
+	"gomen/routes"
+	"io"
+	"log"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiTestDatabase is the in-memory SQLite database of test:api, shared by
+// the connections of the pool
+const apiTestDatabase = "file:gomen_test_api?mode=memory&cache=shared"
+
+func init() {
+	Register(&apiTestCommand{})
+}
+
+type apiTestCommand struct {
+	collection string
+	junit      string
+	seed       bool
+	verbose    bool
+}
+
+func (c *apiTestCommand) Name() string { return "test:api" }
+
+func (c *apiTestCommand) Description() string {
+	return "Run a Postman collection against the application with an in-memory database"
+}
+
+func (c *apiTestCommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.collection, "collection", "postman.json", "Postman collection to run")
+	fs.StringVar(&c.junit, "junit", "junit.xml", "File to write the JUnit XML report to")
+	fs.BoolVar(&c.seed, "seed", false, "Run DatabaseSeeder before the requests")
+	fs.BoolVar(&c.verbose, "verbose", false, "Print the request log of the application")
+}
+
+// The command connects to its own in-memory database, replacing the
+// connection of the process, so it cannot be scheduled either
+func (c *apiTestCommand) WithoutDatabase() {}
+
+func (c *apiTestCommand) WithoutSchedule() {}
+
+func (c *apiTestCommand) Handle(ctx *Context) error {
+	collection, err := postman.Load(c.collection)
+	if err != nil {
+		return err
+	}
+
+	ctx.Config.App.Debug = false
+	ctx.Config.Database.Driver = "sqlite"
+	ctx.Config.Database.Database = apiTestDatabase
+	if err := config.ConnectDatabase(); err != nil {
+		return err
+	}
+	if err := migrations.Migrate(); err != nil {
+		return err
+	}
+	if c.seed {
+		if err := seeders.Seed(); err != nil {
+			return err
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := routes.NewRouter()
+
+	if !c.verbose {
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+		helpers.Logger = helpers.Logger.Output(io.Discard)
+	}
+
+	runner := &postman.Runner{Handler: router}
+	results := runner.Run(collection)
+
+	fmt.Fprintln(ctx.Out)
+	failed, unchecked := 0, 0
+	for _, result := range results {
+		name := result.Name
+		if result.Folder != "" {
+			name = result.Folder + " / " + name
+		}
+
+		mark := "\033[32m✓\033[0m"
+		if result.Failed() {
+			mark = "\033[31m✗\033[0m"
+			failed++
+		} else if result.Unchecked() {
+			mark = "\033[33m-\033[0m"
+			unchecked++
+		}
+		fmt.Fprintf(ctx.Out, "%s %s  %s %s → %d (%s)\n", mark, name, result.Method, result.URL, result.Status, checks(result.Checks))
+
+		for _, failure := range result.Failures {
+			fmt.Fprintf(ctx.Out, "    \033[31m%s\033[0m\n", failure)
+		}
+		for _, statement := range result.Unsupported {
+			fmt.Fprintf(ctx.Out, "    \033[33mskipped unsupported: %s\033[0m\n", statement)
+		}
+	}
+
+	if err := postman.WriteJUnit(c.junit, collection.Info.Name, results); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "\n%d requests, %d passed, %d failed, %d without checks\n", len(results), len(results)-failed-unchecked, failed, unchecked)
+	fmt.Fprintf(ctx.Out, "JUnit report written: %s\n", c.junit)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests failed", failed, len(results))
+	}
+	return nil
+}
+
+func checks(n int) string {
+	switch n {
+	case 0:
+		return "no checks"
+	case 1:
+		return "1 check"
+	}
+	return fmt.Sprintf("%d checks", n)
+}
@@ -0,0 +1,14 @@
+// Package commands holds the console commands of the application. A command
+// implements console.Command and registers itself from init():
+//
+//	func init() {
+//		console.Register(&PruneUsersCommand{})
+//	}
+//
+// It then runs with `gomen users:prune` or `./bin/app users:prune`, and gets
+// the configuration and the database connection through its console.Context.
+// Create one with `gomen make:command DeactivateUsers --command=users:deactivate`.
+//
+// The recurring tasks are declared in schedule.go and run by the scheduler
+// (see package gomen/app/console/schedule).
+package commands
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"gomen/app/console"
+	"gomen/app/models"
+	"time"
+)
+
+func init() {
+	console.Register(&PruneUsersCommand{})
+}
+
+type PruneUsersCommand struct {
+	days   int
+	dryRun bool
+}
+
+func (c *PruneUsersCommand) Name() string { return "users:prune" }
+
+func (c *PruneUsersCommand) Description() string {
+	return "Permanently delete users that were soft deleted a while ago"
+}
+
+func (c *PruneUsersCommand) Flags(fs *flag.FlagSet) {
+	fs.IntVar(&c.days, "days", 30, "Delete users soft deleted more than this many days ago")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "Report what would change without changing it")
+}
+
+// Handle runs the command. ctx.Config is the configuration, ctx.DB the
+// database connection and ctx.Args the arguments left after the flags.
+// Return console.UsageError for wrong arguments.
+func (c *PruneUsersCommand) Handle(ctx *console.Context) error {
+	if c.days < 0 {
+		return console.UsageError("--days must not be negative")
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -c.days)
+	query := ctx.DB.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+
+	if c.dryRun {
+		var count int64
+		if err := query.Model(&models.User{}).Count(&count).Error; err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.Out, "%d users would be deleted\n", count)
+		return nil
+	}
+
+	result := query.Delete(&models.User{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	fmt.Fprintf(ctx.Out, "%d users deleted\n", result.RowsAffected)
+	return nil
+}
@@ -0,0 +1,9 @@
+package commands
+
+import "gomen/app/console/schedule"
+
+// The scheduled tasks of the application. They run with `gomen schedule:run`
+// or `gomen serve --schedule`; `gomen schedule:list` shows when.
+func init() {
+	schedule.Command("users:prune").DailyAt("02:00").WithoutOverlapping()
+}
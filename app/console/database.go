@@ -0,0 +1,234 @@
+package console
+
+import (
+	"encoding/json"
+	"flag"
+	"gomen/database/fixtures"
+	"gomen/database/guard"
+	"gomen/database/migrations"
+	"gomen/database/seeders"
+	"strings"
+)
+
+func init() {
+	Register(
+		&migrateCommand{},
+		&rollbackCommand{},
+		&statusCommand{},
+		&diffCommand{},
+		&resetCommand{},
+		&freshCommand{},
+		&schemaDumpCommand{},
+		&seedCommand{},
+		&fixturesCommand{},
+	)
+}
+
+type migrateCommand struct {
+	pretend bool
+	force   bool
+}
+
+func (c *migrateCommand) Name() string { return "migrate" }
+
+func (c *migrateCommand) Description() string { return "Run pending database migrations" }
+
+func (c *migrateCommand) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.pretend, "pretend", false, "Print the SQL instead of running it")
+	fs.BoolVar(&c.force, "force", false, "Skip the confirmation in production")
+}
+
+func (c *migrateCommand) Handle(ctx *Context) error {
+	if c.pretend {
+		return migrations.Pretend()
+	}
+
+	if err := guard.Confirm(c.Name(), c.force); err != nil {
+		return err
+	}
+	return migrations.Migrate()
+}
+
+type rollbackCommand struct {
+	step    int
+	pretend bool
+	force   bool
+}
+
+func (c *rollbackCommand) Name() string { return "migrate:rollback" }
+
+func (c *rollbackCommand) Description() string { return "Roll back the last batch of migrations" }
+
+func (c *rollbackCommand) Flags(fs *flag.FlagSet) {
+	fs.IntVar(&c.step, "step", 0, "Number of migrations to roll back (default: last batch)")
+	fs.BoolVar(&c.pretend, "pretend", false, "Print the SQL instead of running it")
+	fs.BoolVar(&c.force, "force", false, "Skip the confirmation in production")
+}
+
+func (c *rollbackCommand) Handle(ctx *Context) error {
+	if c.pretend {
+		return migrations.PretendRollback(c.step)
+	}
+
+	if err := guard.Confirm(c.Name(), c.force); err != nil {
+		return err
+	}
+	return migrations.Rollback(c.step)
+}
+
+type statusCommand struct {
+	json bool
+}
+
+func (c *statusCommand) Name() string { return "migrate:status" }
+
+func (c *statusCommand) Description() string { return "Show the status of each migration" }
+
+func (c *statusCommand) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.json, "json", false, "Print the statuses as JSON")
+}
+
+func (c *statusCommand) Handle(ctx *Context) error {
+	if !c.json {
+		return migrations.Status()
+	}
+
+	statuses, err := migrations.Statuses()
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(ctx.Out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statuses)
+}
+
+type diffCommand struct{}
+
+func (c *diffCommand) Name() string { return "migrate:diff" }
+
+func (c *diffCommand) Description() string {
+	return "Generate a migration for added or dropped columns and indexes (type, size and null changes are not detected)"
+}
+
+func (c *diffCommand) Flags(fs *flag.FlagSet) {}
+
+func (c *diffCommand) Handle(ctx *Context) error {
+	if ctx.Arg(0) == "" {
+		return UsageError("migration name is required, e.g. migrate:diff add_sku_to_products_table")
+	}
+
+	return migrations.GenerateDiff(ctx.Arg(0))
+}
+
+type resetCommand struct {
+	force bool
+}
+
+func (c *resetCommand) Name() string { return "migrate:reset" }
+
+func (c *resetCommand) Description() string { return "Roll back all migrations" }
+
+func (c *resetCommand) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.force, "force", false, "Skip the confirmation in production")
+}
+
+func (c *resetCommand) Handle(ctx *Context) error {
+	if err := guard.ConfirmDestructive(c.Name(), c.force); err != nil {
+		return err
+	}
+	return migrations.Reset()
+}
+
+type freshCommand struct {
+	seed  bool
+	force bool
+}
+
+func (c *freshCommand) Name() string { return "migrate:fresh" }
+
+func (c *freshCommand) Description() string { return "Drop all tables and re-run all migrations" }
+
+func (c *freshCommand) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.seed, "seed", false, "Run database seeders after migrating")
+	fs.BoolVar(&c.force, "force", false, "Skip the confirmation in production")
+}
+
+func (c *freshCommand) Handle(ctx *Context) error {
+	if err := guard.ConfirmDestructive(c.Name(), c.force); err != nil {
+		return err
+	}
+	if err := migrations.Fresh(); err != nil {
+		return err
+	}
+	if c.seed {
+		return seeders.Seed()
+	}
+	return nil
+}
+
+type schemaDumpCommand struct {
+	prune bool
+}
+
+func (c *schemaDumpCommand) Name() string { return "schema:dump" }
+
+func (c *schemaDumpCommand) Description() string {
+	return "Dump the database schema and migration records to database/schema"
+}
+
+func (c *schemaDumpCommand) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.prune, "prune", false, "Delete the migration files included in the dump")
+}
+
+func (c *schemaDumpCommand) Handle(ctx *Context) error {
+	return migrations.Dump(c.prune)
+}
+
+type seedCommand struct {
+	class string
+	force bool
+}
+
+func (c *seedCommand) Name() string { return "seed" }
+
+func (c *seedCommand) Description() string { return "Run database seeders" }
+
+func (c *seedCommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.class, "class", "DatabaseSeeder", "Seeder to run, e.g. ProductSeeder")
+	fs.BoolVar(&c.force, "force", false, "Skip the confirmation in production")
+}
+
+func (c *seedCommand) Handle(ctx *Context) error {
+	if err := guard.Confirm(c.Name(), c.force); err != nil {
+		return err
+	}
+	return seeders.SeedClass(c.class)
+}
+
+type fixturesCommand struct {
+	only  string
+	force bool
+}
+
+func (c *fixturesCommand) Name() string { return "db:fixtures" }
+
+func (c *fixturesCommand) Description() string {
+	return "Load JSON, YAML and CSV fixtures from database/fixtures"
+}
+
+func (c *fixturesCommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.only, "only", "", "Comma-separated tables to load, e.g. products,users")
+	fs.BoolVar(&c.force, "force", false, "Skip the confirmation in production")
+}
+
+func (c *fixturesCommand) Handle(ctx *Context) error {
+	if err := guard.Confirm(c.Name(), c.force); err != nil {
+		return err
+	}
+
+	var tables []string
+	if c.only != "" {
+		tables = strings.Split(c.only, ",")
+	}
+	return fixtures.Load(tables)
+}
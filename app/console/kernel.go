@@ -0,0 +1,284 @@
+// Package console is the command kernel of the application binary.
+//
+//	./bin/app serve --port=9000
+//	./bin/app migrate --pretend
+//	./bin/app help migrate:rollback
+//
+// Each command declares its own flags and help text. Run returns 0 on
+// success, 1 when the command fails and 2 on usage errors.
+package console
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gomen/config"
+	"gomen/helpers"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// Command is a subcommand of the application binary
+type Command interface {
+	// Name is the command as typed, e.g. "migrate:rollback"
+	Name() string
+	// Description is the one line shown by help
+	Description() string
+	// Flags defines the command's flags
+	Flags(fs *flag.FlagSet)
+	// Handle runs the command after its flags are parsed
+	Handle(ctx *Context) error
+}
+
+// withoutDatabase is implemented by commands that run without connecting
+// to the database, such as route:list
+type withoutDatabase interface {
+	WithoutDatabase()
+}
+
+// withoutSchedule is implemented by commands that take over the process,
+// such as serve, and cannot run as a scheduled task
+type withoutSchedule interface {
+	WithoutSchedule()
+}
+
+// Context is passed to Command.Handle
+type Context struct {
+	// Args are the arguments left after the flags
+	Args   []string
+	Config *config.Config
+	// DB is nil for commands that run without a database
+	DB  *gorm.DB
+	Out io.Writer
+}
+
+// Arg returns the i-th positional argument, or "" when missing
+func (c *Context) Arg(i int) string {
+	if i < len(c.Args) {
+		return c.Args[i]
+	}
+	return ""
+}
+
+type usageError struct{ message string }
+
+func (e usageError) Error() string { return e.message }
+
+// UsageError reports wrong arguments. Run prints the command's help and exits with ExitUsage.
+func UsageError(format string, args ...interface{}) error {
+	return usageError{message: fmt.Sprintf(format, args...)}
+}
+
+var commands = map[string]Command{}
+
+// kernelPackage is the package of the built-in commands. Commands from other
+// packages, such as app/console/commands, are listed as app commands.
+var kernelPackage = reflect.TypeOf(Context{}).PkgPath()
+
+func isBuiltin(cmd Command) bool {
+	typ := reflect.TypeOf(cmd)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.PkgPath() == kernelPackage
+}
+
+// Register adds commands to the kernel
+func Register(cmds ...Command) {
+	for _, cmd := range cmds {
+		commands[cmd.Name()] = cmd
+	}
+}
+
+// Run runs the command named by args[0] and returns the process exit code.
+// Without arguments it starts the server.
+func Run(args []string) int {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "--help":
+		if len(args) > 1 {
+			if cmd, ok := commands[args[1]]; ok {
+				printHelp(os.Stdout, cmd)
+				return ExitOK
+			}
+		}
+		printCommands(os.Stdout)
+		return ExitOK
+	case "list":
+		if len(args) > 1 && args[1] == "--json" {
+			if err := printCommandsJSON(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				return ExitFailure
+			}
+			return ExitOK
+		}
+		printCommands(os.Stdout)
+		return ExitOK
+	case "-migrate", "--migrate", "-seed", "--seed":
+		fmt.Fprintf(os.Stderr, "The %s flag was replaced by the %s command\n", name, strings.TrimLeft(name, "-"))
+		return ExitUsage
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\nRun 'help' to see available commands\n", name)
+		return ExitUsage
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cmd.Flags(fs)
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printHelp(os.Stdout, cmd)
+			return ExitOK
+		}
+		fmt.Fprintf(os.Stderr, "Error: %s\n\n", err)
+		printHelp(os.Stderr, cmd)
+		return ExitUsage
+	}
+
+	config.Load()
+	cfg := config.Get()
+	helpers.InitLogger(cfg.App.Debug, cfg.App.Env)
+
+	ctx := &Context{Args: fs.Args(), Config: cfg, Out: os.Stdout}
+	if _, skip := cmd.(withoutDatabase); !skip {
+		if err := config.ConnectDatabase(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return ExitFailure
+		}
+		ctx.DB = config.GetDB()
+	}
+
+	if err := cmd.Handle(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+
+		var usage usageError
+		if errors.As(err, &usage) {
+			fmt.Fprintln(os.Stderr)
+			printHelp(os.Stderr, cmd)
+			return ExitUsage
+		}
+		return ExitFailure
+	}
+
+	return ExitOK
+}
+
+// call runs a registered command in the current process with the config,
+// database and output of ctx, e.g. for the scheduler. The command runs on
+// a fresh copy so concurrent calls do not share its flag fields.
+func call(ctx *Context, name string, args []string) error {
+	registered, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+
+	cmd := registered
+	if typ := reflect.TypeOf(registered); typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct {
+		cmd = reflect.New(typ.Elem()).Interface().(Command)
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cmd.Flags(fs)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	commandCtx := &Context{Args: fs.Args(), Config: ctx.Config, DB: ctx.DB, Out: ctx.Out}
+	if _, skip := cmd.(withoutDatabase); skip {
+		commandCtx.DB = nil
+	}
+	return cmd.Handle(commandCtx)
+}
+
+func printHelp(w io.Writer, cmd Command) {
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	cmd.Flags(fs)
+
+	fmt.Fprintf(w, "Usage: %s [flags] [arguments]\n\n%s\n", cmd.Name(), cmd.Description())
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(w, "\nFlags:")
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+}
+
+func sortedNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printCommands(w io.Writer) {
+	var builtin, app []string
+	for _, name := range sortedNames() {
+		if isBuiltin(commands[name]) {
+			builtin = append(builtin, name)
+		} else {
+			app = append(app, name)
+		}
+	}
+
+	fmt.Fprintln(w, "Usage: <command> [flags] [arguments]")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "\nCommands:")
+	for _, name := range builtin {
+		fmt.Fprintf(tw, "  %s\t%s\n", name, commands[name].Description())
+	}
+	if len(app) > 0 {
+		fmt.Fprintln(tw, "\nApp Commands:")
+		for _, name := range app {
+			fmt.Fprintf(tw, "  %s\t%s\n", name, commands[name].Description())
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nRun 'help <command>' for the flags of a command.")
+}
+
+// printCommandsJSON lists the commands for the gomen CLI
+func printCommandsJSON(w io.Writer) error {
+	type entry struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Builtin     bool   `json:"builtin"`
+	}
+
+	list := []entry{}
+	for _, name := range sortedNames() {
+		list = append(list, entry{Name: name, Description: commands[name].Description(), Builtin: isBuiltin(commands[name])})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(list)
+}
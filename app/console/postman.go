@@ -0,0 +1,77 @@
+package console
+
+import (
+	"flag"
+	"fmt"
+	"gomen/internal/openapi"
+	"gomen/internal/postman"
+	"gomen/routes"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	Register(&postmanExportCommand{})
+}
+
+type postmanExportCommand struct {
+	output  string
+	baseURL string
+}
+
+func (c *postmanExportCommand) Name() string { return "postman:export" }
+
+func (c *postmanExportCommand) Description() string {
+	return "Export the registered routes as a Postman collection"
+}
+
+func (c *postmanExportCommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.output, "output", "postman.json", "File to write the collection to")
+	fs.StringVar(&c.baseURL, "base-url", "", "Value of the baseUrl variable (default: http://localhost:APP_PORT)")
+}
+
+func (c *postmanExportCommand) WithoutDatabase() {}
+
+func (c *postmanExportCommand) Handle(ctx *Context) error {
+	if c.baseURL == "" {
+		c.baseURL = "http://localhost:" + ctx.Config.App.Port
+	}
+
+	// The request bodies come from the annotated request structs
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	doc, err := openapi.Generate(root)
+	if err != nil {
+		return err
+	}
+
+	// Keep gin from printing every route while the router is built
+	gin.SetMode(gin.ReleaseMode)
+
+	registered, err := routes.List(routes.NewRouter())
+	if err != nil {
+		return err
+	}
+
+	var list []routes.Route
+	for _, route := range registered {
+		// Swagger UI is not part of the API
+		if route.Path == "/docs" || strings.HasPrefix(route.Path, "/docs/") {
+			continue
+		}
+		list = append(list, route)
+	}
+
+	collection := postman.Build(ctx.Config.App.Name, strings.TrimRight(c.baseURL, "/"), list, doc)
+	if err := collection.Write(c.output); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "\033[32m✓\033[0m Postman collection written: %s (%d requests)\n", c.output, len(list))
+	fmt.Fprintf(ctx.Out, "  → Send the login request first, it stores the token of the protected requests in {{%s}}\n", postman.TokenVariable)
+	return nil
+}
@@ -0,0 +1,87 @@
+package console
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gomen/routes"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	Register(&routeListCommand{})
+}
+
+type routeListCommand struct {
+	method string
+	path   string
+	json   bool
+}
+
+func (c *routeListCommand) Name() string { return "route:list" }
+
+func (c *routeListCommand) Description() string { return "List the registered HTTP routes" }
+
+func (c *routeListCommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.method, "method", "", "Only show routes with this HTTP method, e.g. GET")
+	fs.StringVar(&c.path, "path", "", "Only show routes whose path contains this text, e.g. products")
+	fs.BoolVar(&c.json, "json", false, "Print the routes as JSON")
+}
+
+func (c *routeListCommand) WithoutDatabase() {}
+
+func (c *routeListCommand) Handle(ctx *Context) error {
+	// Keep gin from printing every route while the router is built
+	gin.SetMode(gin.ReleaseMode)
+
+	registered, err := routes.List(routes.NewRouter())
+	if err != nil {
+		return err
+	}
+
+	list := []routes.Route{}
+	for _, route := range registered {
+		if c.method != "" && !strings.EqualFold(route.Method, c.method) {
+			continue
+		}
+		if c.path != "" && !strings.Contains(route.Path, c.path) {
+			continue
+		}
+		list = append(list, route)
+	}
+
+	if c.json {
+		encoder := json.NewEncoder(ctx.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+
+	protected := 0
+	w := tabwriter.NewWriter(ctx.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tNAME\tHANDLER\tACCESS\tMIDDLEWARE")
+	for _, route := range list {
+		access := "public"
+		if route.Auth {
+			access = "auth"
+			protected++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, orDash(route.Name),
+			route.Handler, access, strings.Join(route.Middleware, " > "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "\n%d routes (%d auth, %d public)\n", len(list), protected, len(list)-protected)
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,193 @@
+package console
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gomen/app/console/schedule"
+	"gomen/database/guard"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+func init() {
+	Register(&scheduleRunCommand{}, &scheduleListCommand{})
+}
+
+type scheduleRunCommand struct {
+	once bool
+}
+
+func (c *scheduleRunCommand) Name() string { return "schedule:run" }
+
+func (c *scheduleRunCommand) Description() string {
+	return "Run the scheduled tasks as they become due"
+}
+
+func (c *scheduleRunCommand) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.once, "once", false, "Run the tasks due this minute and exit, e.g. from cron")
+}
+
+func (c *scheduleRunCommand) WithoutSchedule() {}
+
+func (c *scheduleRunCommand) Handle(ctx *Context) error {
+	runner, err := newScheduler(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.once {
+		if due := runner.RunDue(time.Now()); len(due) == 0 {
+			fmt.Fprintln(ctx.Out, "No scheduled tasks are due")
+		}
+		runner.Wait()
+		return nil
+	}
+
+	// Finish the running tasks on Ctrl+C or when the container stops
+	work, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	runner.Work(work)
+	return nil
+}
+
+// newScheduler checks the schedule and returns a runner for it. Scheduled
+// commands run in this process with the config and database of ctx, so they
+// cannot answer the confirmation prompt of guarded commands.
+func newScheduler(ctx *Context) (*schedule.Runner, error) {
+	if err := setScheduleTimezone(ctx); err != nil {
+		return nil, err
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range schedule.Events() {
+		if name, _, ok := e.CommandLine(); ok {
+			if err := checkScheduled(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	guard.Interactive = false
+	return &schedule.Runner{
+		DB: ctx.DB,
+		RunCommand: func(name string, args []string) error {
+			return call(ctx, name, args)
+		},
+	}, nil
+}
+
+// checkScheduled returns an error for a scheduled command that is not
+// registered or cannot run as a task
+func checkScheduled(name string) error {
+	cmd, registered := commands[name]
+	if !registered {
+		return fmt.Errorf("scheduled command %q is not registered", name)
+	}
+	if _, ok := cmd.(withoutSchedule); ok {
+		return fmt.Errorf("scheduled command %q cannot run as a scheduled task", name)
+	}
+	return nil
+}
+
+func setScheduleTimezone(ctx *Context) error {
+	if ctx.Config.Schedule.Timezone == "" {
+		return nil
+	}
+
+	location, err := time.LoadLocation(ctx.Config.Schedule.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid SCHEDULE_TIMEZONE %q", ctx.Config.Schedule.Timezone)
+	}
+	schedule.Location = location
+	return nil
+}
+
+type scheduleListCommand struct{}
+
+func (c *scheduleListCommand) Name() string { return "schedule:list" }
+
+func (c *scheduleListCommand) Description() string {
+	return "List the scheduled tasks and when they run next"
+}
+
+func (c *scheduleListCommand) Flags(fs *flag.FlagSet) {}
+
+func (c *scheduleListCommand) WithoutDatabase() {}
+
+func (c *scheduleListCommand) Handle(ctx *Context) error {
+	if err := setScheduleTimezone(ctx); err != nil {
+		return err
+	}
+
+	events := schedule.Events()
+	if len(events) == 0 {
+		fmt.Fprintln(ctx.Out, "No scheduled tasks. Declare them in app/console/commands, e.g.")
+		fmt.Fprintln(ctx.Out, `  schedule.Command("users:prune").DailyAt("02:00")`)
+		return nil
+	}
+
+	now := time.Now()
+	invalid := 0
+	w := tabwriter.NewWriter(ctx.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EXPRESSION\tTIMEZONE\tTASK\tNEXT DUE\tOPTIONS")
+	for _, e := range events {
+		next := "-"
+		var options []string
+		if e.WithoutOverlap() {
+			options = append(options, "without overlapping")
+		}
+
+		if err := e.Err(); err != nil {
+			invalid++
+			options = append(options, "error: "+err.Error())
+		} else if name, _, ok := e.CommandLine(); ok && checkScheduled(name) != nil {
+			invalid++
+			if commands[name] == nil {
+				options = append(options, "error: unknown command")
+			} else {
+				options = append(options, "error: cannot be scheduled")
+			}
+		} else if at := e.NextRun(now); !at.IsZero() {
+			next = fmt.Sprintf("%s (%s)", at.Format("2006-01-02 15:04 MST"), until(at.Sub(now)))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", orDash(e.Expression()), e.Location(), e,
+			next, orDash(strings.Join(options, ", ")))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "\n%d scheduled tasks\n", len(events))
+	if invalid > 0 {
+		return fmt.Errorf("%d scheduled tasks are invalid", invalid)
+	}
+	return nil
+}
+
+// until formats the time left before a run, e.g. "in 3h 12m"
+func until(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "in less than a minute"
+	}
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("in %dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("in %dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("in %dm", minutes)
+	}
+}
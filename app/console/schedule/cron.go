@@ -0,0 +1,228 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five field cron expression:
+//
+//	┌───────────── minute (0-59)
+//	│ ┌─────────── hour (0-23)
+//	│ │ ┌───────── day of the month (1-31)
+//	│ │ │ ┌─────── month (1-12 or JAN-DEC)
+//	│ │ │ │ ┌───── day of the week (0-7 or SUN-SAT, 0 and 7 are Sunday)
+//	* * * * *
+//
+// Fields accept lists (1,15), ranges (1-5), steps (*/5, 10-40/10) and the
+// macros @yearly, @monthly, @weekly, @daily, @midnight and @hourly. As in
+// cron, when neither day field starts with * a day matches if either the
+// day of the month or the day of the week matches.
+//
+// Times are wall clock times. A time skipped when daylight saving time
+// starts does not run that day. A time repeated when it ends runs once,
+// unless the expression runs every hour.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// allHours are the bits of an hour field of *
+const allHours = 1<<24 - 1
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+// ParseCron parses a cron expression such as "*/5 * * * *" or "@daily"
+func ParseCron(expression string) (*Cron, error) {
+	spec := strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expression, len(fields))
+	}
+
+	c := &Cron{domAny: unrestricted(fields[2]), dowAny: unrestricted(fields[4])}
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: minute: %w", expression, err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: hour: %w", expression, err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: day of month: %w", expression, err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: month: %w", expression, err)
+	}
+	if c.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: day of week: %w", expression, err)
+	}
+
+	// 7 is Sunday too
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	return c, nil
+}
+
+// unrestricted reports whether a day field starts with *, which makes the
+// day fields combine with AND instead of OR
+func unrestricted(field string) bool {
+	return strings.HasPrefix(field, "*") || field == "?"
+}
+
+// parseField returns the bits of the values a field matches
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			low, high = min, max
+		default:
+			from, to, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			if low, err = fieldValue(from, names); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = fieldValue(to, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// 5/15 is 5-max/15
+				high = max
+			}
+		}
+
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func fieldValue(s string, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return value, nil
+}
+
+// Matches reports whether the expression is due in the minute of t, in the
+// location of t
+func (c *Cron) Matches(t time.Time) bool {
+	return c.minute&(1<<uint(t.Minute())) != 0 &&
+		c.hour&(1<<uint(t.Hour())) != 0 &&
+		c.month&(1<<uint(t.Month())) != 0 &&
+		c.dayMatches(t) &&
+		!c.repeated(t)
+}
+
+// repeated reports whether the wall clock time of t already occurred, in
+// the hour repeated when daylight saving time ends. Expressions for every
+// hour run in both occurrences, the others in the first one only.
+func (c *Cron) repeated(t time.Time) bool {
+	if c.hour == allHours {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, before := t.Add(-3 * time.Hour).Zone()
+	if before <= offset {
+		return false
+	}
+	earlier := t.Add(-time.Duration(before-offset) * time.Second)
+	return earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first minute after t the expression is due, in the
+// location of t. It returns the zero time when there is none within five
+// years, e.g. for 30 February.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		next := t
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			// Added rather than built with time.Date, which turns the hour
+			// skipped when daylight saving time starts into the one before
+			next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0 || c.repeated(t):
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// A midnight skipped by daylight saving time can move time.Date back
+		if !next.After(t) {
+			next = t.Add(time.Minute)
+		}
+		t = next
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,158 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{"* * * * *", false},
+		{"*/15 * * * *", false},
+		{"0 9-17/2 * * 1-5", false},
+		{"5,35 0 1,15 jan,JUL sun-sat", false},
+		{"0 0 * * 7", false},
+		{"@daily", false},
+		{" @HOURLY ", false},
+		{"? * ? * *", false},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"*/x * * * *", true},
+		{"a * * * *", true},
+		{"* * * foo *", true},
+		{"1,,2 * * * *", true},
+		{"@reboot", true},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCron(tt.expression)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCron(%q) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		expression string
+		at         string
+		want       bool
+	}{
+		{"* * * * *", "2026-10-18 10:46", true},
+		{"*/15 * * * *", "2026-10-18 10:45", true},
+		{"*/15 * * * *", "2026-10-18 10:46", false},
+		{"10-40/10 * * * *", "2026-10-18 10:30", true},
+		{"10-40/10 * * * *", "2026-10-18 10:35", false},
+		{"10-40/10 * * * *", "2026-10-18 10:50", false},
+		{"5/15 * * * *", "2026-10-18 10:05", true},
+		{"5/15 * * * *", "2026-10-18 10:50", true},
+		{"5/15 * * * *", "2026-10-18 10:00", false},
+		{"0 9-17 * * *", "2026-10-18 17:00", true},
+		{"0 9-17 * * *", "2026-10-18 18:00", false},
+		{"0 0 * jan,jul *", "2026-07-01 00:00", true},
+		{"0 0 * jan,jul *", "2026-08-01 00:00", false},
+		// 7 and sun are Sunday; 2026-10-18 is a Sunday
+		{"0 0 * * 7", "2026-10-18 00:00", true},
+		{"0 0 * * sun", "2026-10-18 00:00", true},
+		{"0 0 * * mon-fri", "2026-10-18 00:00", false},
+		// Both day fields restricted: either matches. 2026-11-06 and
+		// 2026-11-13 are Fridays.
+		{"0 0 13 * 5", "2026-11-06 00:00", true},
+		{"0 0 13 * 5", "2026-11-13 00:00", true},
+		{"0 0 13 * 5", "2026-10-13 00:00", true},
+		{"0 0 13 * 5", "2026-10-14 00:00", false},
+		// A day field starting with * restricts nothing, so both must match
+		{"0 0 * * 5", "2026-10-13 00:00", false},
+		{"0 0 13 * *", "2026-11-06 00:00", false},
+		{"0 0 */2 * 5", "2026-11-13 00:00", true},
+		{"0 0 */2 * 5", "2026-11-06 00:00", false},
+		{"0 0 */2 * 5", "2026-11-05 00:00", false},
+		{"@hourly", "2026-10-18 10:00", true},
+		{"@hourly", "2026-10-18 10:01", false},
+		{"@weekly", "2026-10-18 00:00", true},
+	}
+
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expression)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expression, err)
+		}
+		if got := cron.Matches(at(tt.at)); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.expression, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	jakarta := mustLocation(t, "Asia/Jakarta")
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"later today", "0 2 * * *", time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC), time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC)},
+		{"tomorrow", "0 2 * * *", time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)},
+		{"after the current minute", "* * * * *", time.Date(2026, 10, 18, 10, 0, 30, 0, time.UTC), time.Date(2026, 10, 18, 10, 1, 0, 0, time.UTC)},
+		{"step", "*/15 * * * *", time.Date(2026, 10, 18, 10, 46, 0, 0, time.UTC), time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC)},
+		{"next month", "0 0 1 * *", time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"day of month or week", "0 0 13 * 5", time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"weekdays", "0 9 * * mon-fri", time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"in the location of from", "0 8 * * *", time.Date(2026, 10, 18, 9, 0, 0, 0, jakarta), time.Date(2026, 10, 19, 8, 0, 0, 0, jakarta)},
+		// Daylight saving time starts at 02:00 on 2026-03-08, so 02:30 is skipped that day
+		{"dst start skips", "30 2 * * *", time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), time.Date(2026, 3, 9, 2, 30, 0, 0, newYork)},
+		{"dst start hourly", "0 * * * *", time.Date(2026, 3, 8, 1, 30, 0, 0, newYork), time.Date(2026, 3, 8, 3, 0, 0, 0, newYork)},
+		// It ends at 02:00 on 2026-11-01, so 01:30 happens twice and runs once
+		{"dst end first", "30 1 * * *", time.Date(2026, 10, 31, 12, 0, 0, 0, newYork), time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+		{"dst end once", "30 1 * * *", time.Date(2026, 11, 1, 5, 31, 0, 0, time.UTC).In(newYork), time.Date(2026, 11, 2, 1, 30, 0, 0, newYork)},
+		{"dst end every hour", "30 * * * *", time.Date(2026, 11, 1, 5, 31, 0, 0, time.UTC).In(newYork), time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expression, err)
+			}
+			got := cron.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("%q next after %s = %s, want %s", tt.expression, tt.from, got, tt.want.In(tt.from.Location()))
+			}
+			if !got.IsZero() && !cron.Matches(got) {
+				t.Errorf("%q does not match its next run %s", tt.expression, got)
+			}
+		})
+	}
+}
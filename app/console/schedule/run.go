@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"gomen/database/lock"
+	"gomen/helpers"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Runner runs the tasks that are due
+type Runner struct {
+	// DB holds the locks of the tasks declared WithoutOverlapping
+	DB *gorm.DB
+	// RunCommand runs a console command in the current process
+	RunCommand func(name string, args []string) error
+
+	running sync.WaitGroup
+}
+
+// RunDue starts the tasks due in the minute of now and returns them. The
+// tasks run concurrently; Wait waits for them.
+func (r *Runner) RunDue(now time.Time) []*Event {
+	var due []*Event
+	for _, e := range events {
+		if !e.IsDue(now) {
+			continue
+		}
+
+		due = append(due, e)
+		r.running.Add(1)
+		go func(e *Event) {
+			defer r.running.Done()
+			r.run(e)
+		}(e)
+	}
+	return due
+}
+
+// Wait waits for the running tasks to finish
+func (r *Runner) Wait() {
+	r.running.Wait()
+}
+
+// Work runs the due tasks at the start of every minute until ctx is done,
+// then waits for the running tasks
+func (r *Runner) Work(ctx context.Context) {
+	helpers.Info("Scheduler started").Int("tasks", len(events)).Str("timezone", Location.String()).Msg("Scheduler started")
+
+	for {
+		next := time.Now().Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			helpers.Info("Scheduler stopping").Msg("Scheduler stopping, waiting for running tasks")
+			r.Wait()
+			return
+		case <-timer.C:
+			r.RunDue(next)
+		}
+	}
+}
+
+func (r *Runner) run(e *Event) {
+	if !e.overlap {
+		held, ok, err := lock.TryAcquireExpiring(r.DB, "schedule:"+e.String(), e.expiry)
+		if err != nil {
+			helpers.Error(err, "Scheduled task failed").Str("task", e.String()).Msg("Failed to take the lock of a scheduled task")
+			return
+		}
+		if !ok {
+			event := helpers.Warn("Scheduled task skipped").Str("task", e.String())
+			if holder := lock.Holder(r.DB, "schedule:"+e.String()); holder != "" {
+				event = event.Str("held_by", holder)
+			}
+			event.Msg("Scheduled task skipped, the previous run is still running")
+			return
+		}
+		defer func() {
+			if err := held.Release(); err != nil {
+				helpers.Error(err, "Scheduled task lock").Str("task", e.String()).Msg("Failed to release the lock of a scheduled task")
+			}
+		}()
+	}
+
+	helpers.Info("Scheduled task started").Str("task", e.String()).Msg("Scheduled task started")
+	start := time.Now()
+
+	if err := r.call(e); err != nil {
+		helpers.Error(err, "Scheduled task failed").Str("task", e.String()).Dur("duration", time.Since(start)).Msg("Scheduled task failed")
+		return
+	}
+	helpers.Info("Scheduled task finished").Str("task", e.String()).Dur("duration", time.Since(start)).Msg("Scheduled task finished")
+}
+
+// call runs the task, turning a panic into an error so one task cannot
+// stop the scheduler. A task that exits the process, e.g. with helpers.DD
+// or helpers.Fatal, still stops it: commands must return their errors.
+func (r *Runner) call(e *Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	if name, args, ok := e.CommandLine(); ok {
+		return r.RunCommand(name, args)
+	}
+	return e.fn()
+}
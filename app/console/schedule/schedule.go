@@ -0,0 +1,264 @@
+// Package schedule declares the recurring tasks of the application, so a
+// deployment needs no crontab entries of its own:
+//
+//	schedule.Command("users:prune").DailyAt("02:00").WithoutOverlapping()
+//	schedule.Func(warmCache).Name("cache:warm").EveryFiveMinutes()
+//	schedule.Command("reports:send", "--weekly").Cron("0 8 * * 1").Timezone("Asia/Jakarta")
+//
+// Tasks are declared from init() in app/console/commands and run by
+// `gomen schedule:run`, or by `gomen serve --schedule`. `gomen schedule:list`
+// shows them with their next run.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Location is the timezone of the tasks declared without Timezone. The
+// console sets it from SCHEDULE_TIMEZONE.
+var Location = time.Local
+
+// Event is a scheduled task
+type Event struct {
+	name       string
+	command    string
+	args       []string
+	fn         func() error
+	expression string
+	cron       *Cron
+	location   *time.Location
+	// overlap is false for tasks declared WithoutOverlapping
+	overlap bool
+	// expiry is how long the lock of WithoutOverlapping is held at most
+	expiry time.Duration
+	err    error
+}
+
+var events []*Event
+
+// Command schedules the console command name with its arguments, e.g.
+// Command("users:prune", "--days=90")
+func Command(name string, args ...string) *Event {
+	e := &Event{command: name, args: args, overlap: true}
+	e.name = strings.TrimSpace(strings.Join(append([]string{name}, args...), " "))
+	events = append(events, e)
+	return e
+}
+
+// Func schedules a function. Give it a Name to tell it apart in the logs
+// and schedule:list, and to run it WithoutOverlapping.
+func Func(fn func() error) *Event {
+	e := &Event{fn: fn, overlap: true}
+	if fn == nil {
+		e.err = errors.New("schedule.Func: nil function")
+	}
+	events = append(events, e)
+	return e
+}
+
+// Events returns the scheduled tasks in declaration order
+func Events() []*Event {
+	return events
+}
+
+// Validate returns the declaration errors of every task, such as an invalid
+// cron expression or timezone
+func Validate() error {
+	var messages []string
+	for _, e := range events {
+		if err := e.Err(); err != nil {
+			messages = append(messages, fmt.Sprintf("%s: %s", e, err))
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("invalid schedule:\n  %s", strings.Join(messages, "\n  "))
+	}
+	return nil
+}
+
+// Cron runs the task on a cron expression, e.g. "*/15 9-17 * * 1-5"
+func (e *Event) Cron(expression string) *Event {
+	cron, err := ParseCron(expression)
+	e.expression = expression
+	e.cron = cron
+	e.fail(err)
+	return e
+}
+
+// EveryMinute runs the task every minute
+func (e *Event) EveryMinute() *Event { return e.Cron("* * * * *") }
+
+// EveryFiveMinutes runs the task every five minutes
+func (e *Event) EveryFiveMinutes() *Event { return e.Cron("*/5 * * * *") }
+
+// EveryTenMinutes runs the task every ten minutes
+func (e *Event) EveryTenMinutes() *Event { return e.Cron("*/10 * * * *") }
+
+// EveryFifteenMinutes runs the task every fifteen minutes
+func (e *Event) EveryFifteenMinutes() *Event { return e.Cron("*/15 * * * *") }
+
+// EveryThirtyMinutes runs the task every thirty minutes
+func (e *Event) EveryThirtyMinutes() *Event { return e.Cron("*/30 * * * *") }
+
+// Hourly runs the task at the start of every hour
+func (e *Event) Hourly() *Event { return e.Cron("0 * * * *") }
+
+// HourlyAt runs the task every hour at minute
+func (e *Event) HourlyAt(minute int) *Event {
+	return e.Cron(fmt.Sprintf("%d * * * *", minute))
+}
+
+// Daily runs the task every day at midnight
+func (e *Event) Daily() *Event { return e.Cron("0 0 * * *") }
+
+// DailyAt runs the task every day at a time such as "02:00"
+func (e *Event) DailyAt(at string) *Event {
+	return e.at(at, "* * *")
+}
+
+// Weekly runs the task on Sunday at midnight
+func (e *Event) Weekly() *Event { return e.Cron("0 0 * * 0") }
+
+// WeeklyOn runs the task every week on day at a time such as "08:30"
+func (e *Event) WeeklyOn(day time.Weekday, at string) *Event {
+	return e.at(at, fmt.Sprintf("* * %d", day))
+}
+
+// Monthly runs the task on the first day of the month at midnight
+func (e *Event) Monthly() *Event { return e.Cron("0 0 1 * *") }
+
+// MonthlyOn runs the task every month on day at a time such as "08:30"
+func (e *Event) MonthlyOn(day int, at string) *Event {
+	return e.at(at, fmt.Sprintf("%d * *", day))
+}
+
+// at runs the task at a time of day, with the day fields of a cron expression
+func (e *Event) at(at, days string) *Event {
+	hour, minute, err := parseTime(at)
+	if err != nil {
+		e.fail(err)
+		return e
+	}
+	return e.Cron(fmt.Sprintf("%d %d %s", minute, hour, days))
+}
+
+// parseTime parses "HH:MM"
+func parseTime(at string) (hour, minute int, err error) {
+	h, m, ok := strings.Cut(at, ":")
+	if ok {
+		hour, err = strconv.Atoi(h)
+		if err == nil {
+			minute, err = strconv.Atoi(m)
+		}
+	}
+	if !ok || err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q: expected HH:MM", at)
+	}
+	return hour, minute, nil
+}
+
+// Timezone evaluates the expression in a timezone such as "Asia/Jakarta"
+// instead of Location
+func (e *Event) Timezone(name string) *Event {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		e.fail(fmt.Errorf("invalid timezone %q", name))
+		return e
+	}
+	e.location = location
+	return e
+}
+
+// DefaultLockExpiry is how long the lock of WithoutOverlapping is held at
+// most, unless given
+const DefaultLockExpiry = 24 * time.Hour
+
+// WithoutOverlapping skips a run while the previous one is still running,
+// in any process connected to the database. The lock is a row of the
+// gomen_locks table on every driver; the lock of a run that never finished
+// is taken over after expiresAfter (DefaultLockExpiry), or as soon as its
+// process is gone from this host.
+func (e *Event) WithoutOverlapping(expiresAfter ...time.Duration) *Event {
+	e.overlap = false
+	e.expiry = DefaultLockExpiry
+	if len(expiresAfter) > 0 {
+		e.expiry = expiresAfter[0]
+	}
+	return e
+}
+
+// Name names the task in the logs, schedule:list and the lock of
+// WithoutOverlapping. Commands are named after their command line.
+func (e *Event) Name(name string) *Event {
+	e.name = name
+	return e
+}
+
+// fail keeps the first declaration error
+func (e *Event) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// String returns the name of the task
+func (e *Event) String() string {
+	if e.name == "" {
+		return "closure"
+	}
+	return e.name
+}
+
+// Err returns the declaration error of the task
+func (e *Event) Err() error {
+	switch {
+	case e.err != nil:
+		return e.err
+	case e.cron == nil:
+		return errors.New("no frequency, e.g. EveryMinute() or Cron(\"* * * * *\")")
+	case e.fn != nil && !e.overlap && e.name == "":
+		return errors.New("WithoutOverlapping needs a Name for functions")
+	}
+	return nil
+}
+
+// Expression returns the cron expression of the task
+func (e *Event) Expression() string {
+	return e.expression
+}
+
+// CommandLine returns the console command of a task declared with Command
+func (e *Event) CommandLine() (name string, args []string, ok bool) {
+	return e.command, e.args, e.fn == nil && e.command != ""
+}
+
+// Location returns the timezone the expression is evaluated in
+func (e *Event) Location() *time.Location {
+	if e.location != nil {
+		return e.location
+	}
+	return Location
+}
+
+// WithoutOverlap reports whether the task was declared WithoutOverlapping
+func (e *Event) WithoutOverlap() bool {
+	return !e.overlap
+}
+
+// IsDue reports whether the task runs in the minute of t
+func (e *Event) IsDue(t time.Time) bool {
+	return e.Err() == nil && e.cron.Matches(t.In(e.Location()))
+}
+
+// NextRun returns the next time after t the task runs, or the zero time
+func (e *Event) NextRun(t time.Time) time.Time {
+	if e.Err() != nil {
+		return time.Time{}
+	}
+	return e.cron.Next(t.In(e.Location()))
+}
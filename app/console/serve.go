@@ -0,0 +1,57 @@
+package console
+
+import (
+	"context"
+	"flag"
+	"gomen/helpers"
+	"gomen/routes"
+)
+
+func init() {
+	Register(&serveCommand{})
+}
+
+type serveCommand struct {
+	port     string
+	env      string
+	schedule bool
+}
+
+func (c *serveCommand) Name() string { return "serve" }
+
+func (c *serveCommand) Description() string { return "Start the HTTP server" }
+
+func (c *serveCommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.port, "port", "", "Port to listen on (default: APP_PORT)")
+	fs.StringVar(&c.env, "env", "", "Application environment (default: APP_ENV)")
+	fs.BoolVar(&c.schedule, "schedule", false, "Also run the scheduled tasks (default: SCHEDULE_IN_SERVE)")
+}
+
+func (c *serveCommand) WithoutSchedule() {}
+
+func (c *serveCommand) Handle(ctx *Context) error {
+	if c.port != "" {
+		ctx.Config.App.Port = c.port
+	}
+	if c.env != "" {
+		ctx.Config.App.Env = c.env
+		helpers.InitLogger(ctx.Config.App.Debug, ctx.Config.App.Env)
+	}
+
+	// Run the scheduler in this process instead of a separate schedule:run worker
+	if c.schedule || ctx.Config.Schedule.Serve {
+		runner, err := newScheduler(ctx)
+		if err != nil {
+			return err
+		}
+		go runner.Work(context.Background())
+	}
+
+	router := routes.NewRouter()
+
+	// Start server
+	port := ctx.Config.App.Port
+	helpers.Info("Server starting").Str("port", port).Msg("GoMen API Server")
+
+	return router.Run(":" + port)
+}
@@ -28,7 +28,7 @@ func NewAboutController() *AboutController {
 // @Security BearerAuth
 // @Param page query int false "Page number"
 // @Param per_page query int false "Items per page"
-// @Success 200 {object} responses.PaginatedResponse
+// @Success 200 {object} responses.PaginatedResponse{data=[]models.About}
 // @Failure 401 {object} responses.Response
 // @Router /abouts [get]
 func (ctrl *AboutController) Index(c *gin.Context) {
@@ -50,7 +50,7 @@ func (ctrl *AboutController) Index(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "About ID"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.About}
 // @Failure 404 {object} responses.Response
 // @Router /abouts/{id} [get]
 func (ctrl *AboutController) Show(c *gin.Context) {
@@ -76,7 +76,7 @@ func (ctrl *AboutController) Show(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body requests.CreateAboutRequest true "Create About Request"
-// @Success 201 {object} responses.Response
+// @Success 201 {object} responses.Response{data=models.About}
 // @Failure 400 {object} responses.Response
 // @Router /abouts [post]
 func (ctrl *AboutController) Store(c *gin.Context) {
@@ -109,7 +109,7 @@ func (ctrl *AboutController) Store(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "About ID"
 // @Param request body requests.UpdateAboutRequest true "Update About Request"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.About}
 // @Failure 400 {object} responses.Response
 // @Router /abouts/{id} [put]
 func (ctrl *AboutController) Update(c *gin.Context) {
@@ -93,7 +93,7 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.User}
 // @Failure 401 {object} responses.Response
 // @Router /auth/profile [get]
 func (ctrl *AuthController) GetProfile(c *gin.Context) {
@@ -115,7 +115,7 @@ func (ctrl *AuthController) GetProfile(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body requests.UpdateProfileRequest true "Update Profile Request"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.User}
 // @Failure 400 {object} responses.Response
 // @Router /auth/profile [put]
 func (ctrl *AuthController) UpdateProfile(c *gin.Context) {
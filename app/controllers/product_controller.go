@@ -28,7 +28,7 @@ func NewProductController() *ProductController {
 // @Security BearerAuth
 // @Param page query int false "Page number"
 // @Param per_page query int false "Items per page"
-// @Success 200 {object} responses.PaginatedResponse
+// @Success 200 {object} responses.PaginatedResponse{data=[]models.Product}
 // @Failure 401 {object} responses.Response
 // @Router /products [get]
 func (ctrl *ProductController) Index(c *gin.Context) {
@@ -50,7 +50,7 @@ func (ctrl *ProductController) Index(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Product ID"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.Product}
 // @Failure 404 {object} responses.Response
 // @Router /products/{id} [get]
 func (ctrl *ProductController) Show(c *gin.Context) {
@@ -76,7 +76,7 @@ func (ctrl *ProductController) Show(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body requests.CreateProductRequest true "Create Product Request"
-// @Success 201 {object} responses.Response
+// @Success 201 {object} responses.Response{data=models.Product}
 // @Failure 400 {object} responses.Response
 // @Router /products [post]
 func (ctrl *ProductController) Store(c *gin.Context) {
@@ -109,7 +109,7 @@ func (ctrl *ProductController) Store(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "Product ID"
 // @Param request body requests.UpdateProductRequest true "Update Product Request"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.Product}
 // @Failure 400 {object} responses.Response
 // @Router /products/{id} [put]
 func (ctrl *ProductController) Update(c *gin.Context) {
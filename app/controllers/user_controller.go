@@ -28,7 +28,7 @@ func NewUserController() *UserController {
 // @Security BearerAuth
 // @Param page query int false "Page number"
 // @Param per_page query int false "Items per page"
-// @Success 200 {object} responses.PaginatedResponse
+// @Success 200 {object} responses.PaginatedResponse{data=[]models.User}
 // @Failure 401 {object} responses.Response
 // @Router /users [get]
 func (ctrl *UserController) Index(c *gin.Context) {
@@ -50,7 +50,7 @@ func (ctrl *UserController) Index(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "User ID"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.User}
 // @Failure 404 {object} responses.Response
 // @Router /users/{id} [get]
 func (ctrl *UserController) Show(c *gin.Context) {
@@ -76,7 +76,7 @@ func (ctrl *UserController) Show(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body requests.CreateUserRequest true "Create User Request"
-// @Success 201 {object} responses.Response
+// @Success 201 {object} responses.Response{data=models.User}
 // @Failure 400 {object} responses.Response
 // @Router /users [post]
 func (ctrl *UserController) Store(c *gin.Context) {
@@ -109,7 +109,7 @@ func (ctrl *UserController) Store(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "User ID"
 // @Param request body requests.UpdateUserRequest true "Update User Request"
-// @Success 200 {object} responses.Response
+// @Success 200 {object} responses.Response{data=models.User}
 // @Failure 400 {object} responses.Response
 // @Router /users/{id} [put]
 func (ctrl *UserController) Update(c *gin.Context) {
@@ -1,5 +1,9 @@
 package models
 
+func init() {
+	Register(&Product{})
+}
+
 type Product struct {
 	BaseModel
 	Name        string  `json:"name" gorm:"size:255;not null"`
@@ -0,0 +1,15 @@
+package models
+
+// registered holds the models kept in sync by database migrations
+var registered []interface{}
+
+// Register adds models to the AutoMigrate list. Model files call it from
+// init() so new models are migrated without editing migrate.go.
+func Register(models ...interface{}) {
+	registered = append(registered, models...)
+}
+
+// All returns every registered model
+func All() []interface{} {
+	return registered
+}
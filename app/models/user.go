@@ -1,5 +1,9 @@
 package models
 
+func init() {
+	Register(&User{})
+}
+
 type User struct {
 	BaseModel
 	Name     string `json:"name" gorm:"size:255;not null"`
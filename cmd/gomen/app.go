@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gomen/internal/openapi"
+)
+
+// appBinary is the application binary that runs the application commands
+var appBinary = filepath.Join("bin", "app")
+
+// runApp runs an application command (serve, migrate, route:list, ...) through
+// the built application binary, rebuilding it first when the sources changed.
+// The exit code of the command becomes the exit code of gomen.
+func runApp(args ...string) {
+	binary := appBinary
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	if err := buildApp(binary); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+}
+
+// buildApp builds the application binary when it is missing or older than
+// the newest Go source. Without a Go toolchain an existing binary is used as is.
+func buildApp(binary string) error {
+	info, err := os.Stat(binary)
+	exists := err == nil
+
+	if _, err := exec.LookPath("go"); err != nil {
+		if exists {
+			return nil
+		}
+		return fmt.Errorf("%s not found and no Go toolchain to build it", binary)
+	}
+
+	if exists && !sourcesChangedSince(info.ModTime()) {
+		return nil
+	}
+
+	cmd := exec.Command("go", "build", "-o", binary, ".")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("building %s failed: %w", binary, err)
+	}
+	return nil
+}
+
+// sourcesChangedSince reports whether a source file changed after t: a Go
+// file, go.mod or go.sum at the root, or any non-hidden file below it, since
+// packages embed files such as the generator stubs. Other files at the root
+// are left out, they are usually written by the app (a SQLite database, an
+// exported collection). A directory below the root that changed after t
+// counts too, as a file was added or deleted.
+func sourcesChangedSince(t time.Time) bool {
+	changed := false
+	filepath.WalkDir(".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if changed {
+			return filepath.SkipDir
+		}
+		if path == "." {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			switch entry.Name() {
+			case "bin", "vendor", "node_modules":
+				return filepath.SkipDir
+			}
+		} else if !strings.ContainsRune(path, filepath.Separator) &&
+			filepath.Ext(path) != ".go" && path != "go.mod" && path != "go.sum" {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(t) {
+			changed = true
+		}
+		return nil
+	})
+	return changed
+}
+
+// appCommand is an entry of `bin/app list --json`
+type appCommand struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Builtin     bool   `json:"builtin"`
+}
+
+// printAppCommands lists the commands the project registers in
+// app/console/commands. Outside a project it prints nothing.
+func printAppCommands() {
+	if _, err := os.Stat(filepath.Join("app", "console")); err != nil {
+		return
+	}
+
+	binary := appBinary
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	var list []appCommand
+	err := buildApp(binary)
+	if err == nil {
+		var output []byte
+		output, err = exec.Command(binary, "list", "--json").Output()
+		if err == nil {
+			err = json.Unmarshal(output, &list)
+		}
+	}
+	if err != nil {
+		fmt.Println("\nApp Commands:\n  could not list them:", err)
+		return
+	}
+
+	fmt.Println("\nApp Commands:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	count := 0
+	for _, command := range list {
+		if !command.Builtin {
+			fmt.Fprintf(w, "  %s\t%s\n", command.Name, command.Description)
+			count++
+		}
+	}
+	w.Flush()
+
+	if count == 0 {
+		fmt.Println("  none yet, create one with: gomen make:command <Name>")
+	}
+}
+
+// appRoutes returns the routes the project registers, from
+// `bin/app route:list --json`
+func appRoutes() ([]openapi.Route, error) {
+	binary := appBinary
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	if err := buildApp(binary); err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command(binary, "route:list", "--json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("route:list failed: %w", err)
+	}
+
+	var routes []openapi.Route
+	if err := json.Unmarshal(output, &routes); err != nil {
+		return nil, fmt.Errorf("route:list printed invalid JSON: %w", err)
+	}
+	return routes, nil
+}
+
+// appliedMigrations returns the migrations recorded in schema_migrations,
+// from `bin/app migrate:status --json`
+func appliedMigrations() (map[string]bool, error) {
+	binary := appBinary
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	if err := buildApp(binary); err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command(binary, "migrate:status", "--json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("migrate:status failed: %w", err)
+	}
+
+	var statuses []struct {
+		Name string `json:"name"`
+		Ran  bool   `json:"ran"`
+	}
+	if err := json.Unmarshal(output, &statuses); err != nil {
+		return nil, fmt.Errorf("migrate:status printed invalid JSON: %w", err)
+	}
+
+	applied := map[string]bool{}
+	for _, status := range statuses {
+		if status.Ran {
+			applied[status.Name] = true
+		}
+	}
+	return applied, nil
+}
@@ -3,13 +3,27 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
 
 	"gomen/internal/generator"
 )
 
 const version = "1.0.0"
 
+// destroyers are the destroy:* commands, the counterparts of make:*
+var destroyers = map[string]func(name string){
+	"destroy:resource":   generator.DestroyResource,
+	"destroy:controller": generator.DestroyController,
+	"destroy:model":      generator.DestroyModel,
+	"destroy:migration":  generator.DestroyMigration,
+	"destroy:service":    generator.DestroyService,
+	"destroy:request":    generator.DestroyRequest,
+	"destroy:middleware": generator.DestroyMiddleware,
+	"destroy:seeder":     generator.DestroySeeder,
+	"destroy:factory":    generator.DestroyFactory,
+	"destroy:command":    generator.DestroyCommand,
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -17,16 +31,18 @@ func main() {
 	}
 
 	command := os.Args[1]
+	if strings.HasPrefix(command, "make:") || strings.HasPrefix(command, "destroy:") {
+		os.Args = append(os.Args[:2], generatorFlags(os.Args[2:])...)
+	}
 
 	switch command {
-	case "serve":
-		runGoCommand()
+	case "serve", "route:list",
+		"migrate", "migrate:rollback", "migrate:status", "migrate:diff", "migrate:reset", "migrate:fresh",
+		"schema:dump", "seed", "db:fixtures", "postman:export", "test:api", "schedule:run", "schedule:list":
+		runApp(os.Args[1:]...)
 
-	case "migrate":
-		runGoCommand("-migrate")
-
-	case "seed":
-		runGoCommand("-seed")
+	case "new":
+		newProject(os.Args[2:])
 
 	case "make:controller":
 		if len(os.Args) < 3 {
@@ -84,43 +100,114 @@ func main() {
 		}
 		generator.MakeSeeder(os.Args[2])
 
+	case "make:factory":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: Model name is required")
+			fmt.Println("Usage: gomen make:factory <ModelName>")
+			os.Exit(1)
+		}
+		generator.MakeFactory(os.Args[2])
+
+	case "make:command":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: Command name is required")
+			fmt.Println("Usage: gomen make:command <CommandName> [--command=users:prune]")
+			os.Exit(1)
+		}
+		name := ""
+		commandName := ""
+		for _, arg := range os.Args[2:] {
+			if strings.HasPrefix(arg, "--command=") {
+				commandName = strings.TrimPrefix(arg, "--command=")
+			} else if name == "" {
+				name = arg
+			}
+		}
+		generator.MakeCommand(name, commandName)
+
 	case "make:resource":
 		if len(os.Args) < 3 {
 			fmt.Println("Error: Resource name is required")
 			fmt.Println("Usage: gomen make:resource <ResourceName>")
 			os.Exit(1)
 		}
-		generator.MakeResource(os.Args[2])
+		wire := true
+		var specs []string
+		for _, arg := range os.Args[3:] {
+			if arg == "--no-wire" {
+				wire = false
+				continue
+			}
+			specs = append(specs, arg)
+		}
+
+		fields, err := generator.ParseFields(specs)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		generator.MakeResource(os.Args[2], wire, fields...)
+
+	case "destroy:resource", "destroy:controller", "destroy:model", "destroy:migration", "destroy:service",
+		"destroy:request", "destroy:middleware", "destroy:seeder", "destroy:factory", "destroy:command":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: Name is required")
+			fmt.Printf("Usage: gomen %s <Name>\n", command)
+			os.Exit(1)
+		}
+		generator.AppliedMigrations = appliedMigrations
+		destroyers[command](os.Args[2])
+
+	case "openapi:generate":
+		generateOpenAPI(os.Args[2:])
+
+	case "stub:publish":
+		force := len(os.Args) > 2 && os.Args[2] == "--force"
+		generator.PublishStubs(force)
 
 	case "list":
 		printCommands()
+		printAppCommands()
 
 	case "version", "-v", "--version":
 		fmt.Printf("GoMen CLI v%s\n", version)
 
 	case "help", "-h", "--help":
+		if len(os.Args) > 2 {
+			runApp(os.Args[1:]...)
+			return
+		}
 		printUsage()
 
 	default:
-		fmt.Printf("Unknown command: %s\n", command)
-		fmt.Println("Run 'gomen list' to see available commands")
+		// Anything else is left to the application binary, which reports
+		// unknown commands
+		runApp(os.Args[1:]...)
+	}
+
+	if generator.Failed() {
 		os.Exit(1)
 	}
 }
 
-func runGoCommand(args ...string) {
-	cmdArgs := append([]string{"run", "main.go"}, args...)
-	cmd := exec.Command("go", cmdArgs...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	if err := cmd.Run(); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			os.Exit(exitErr.ExitCode())
-		}
-		fmt.Println("Error:", err)
-		os.Exit(1)
+// generatorFlags applies the --dry-run and --force flags shared by the
+// make:* commands and returns the other arguments
+func generatorFlags(args []string) []string {
+	var opts generator.Options
+	var rest []string
+	for _, arg := range args {
+		switch arg {
+		case "--dry-run":
+			opts.DryRun = true
+		case "--force":
+			opts.Force = true
+		default:
+			rest = append(rest, arg)
+		}
 	}
+
+	generator.SetOptions(opts)
+	return rest
 }
 
 func printUsage() {
@@ -136,10 +223,38 @@ func printUsage() {
 Usage:
   gomen <command> [arguments]
 
+Project Commands:
+  new <name>                Create a new project (--module=github.com/acme/myapi, --db=postgres)
+
 Application Commands:
-  serve                     Start the application server
-  migrate                   Run database migrations
-  seed                      Run database seeders
+  serve                     Start the application server (--port=9000, --env=staging,
+                            --schedule to also run the scheduled tasks)
+  migrate                   Run database migrations (--pretend to print the SQL)
+  migrate:rollback          Roll back the last batch (--step=N, --pretend)
+  migrate:status            Show the status of each migration
+  migrate:diff <name>       Generate a migration for added or dropped columns and indexes
+                            (changed column types, sizes and nullability are not detected)
+  migrate:reset             Roll back all migrations
+  migrate:fresh             Drop all tables and re-run migrations (--seed to seed)
+  schema:dump               Dump the schema to database/schema (--prune to delete old migrations)
+  seed                      Run database seeders (--class=ProductSeeder to run one)
+  db:fixtures               Load database/fixtures files (--only=products,users)
+  route:list                List routes with handler and middleware (--method, --path, --json)
+  schedule:run              Run the scheduled tasks as they become due (--once to run this minute's and exit)
+  schedule:list             List the scheduled tasks with their cron expression and next run
+
+  Application commands run through bin/app, which is rebuilt when the sources change.
+  The commands of app/console/commands run the same way; 'gomen list' shows them.
+  Run 'gomen help <command>' for the flags of a command.
+  Database commands ask for confirmation when APP_ENV=production; pass --force to skip it.
+
+Documentation Commands:
+  openapi:generate          Write docs/openapi.json from the swag annotations (--output=path)
+  postman:export            Write postman.json from the registered routes (--output, --base-url)
+
+Testing Commands:
+  test:api                  Run a Postman collection in-process on an in-memory SQLite database
+                            (--collection, --junit, --seed, --verbose)
 
 Generator Commands:
   make:controller <Name>    Create a new controller
@@ -149,30 +264,87 @@ Generator Commands:
   make:request <Name>       Create a new request validation
   make:middleware <Name>    Create a new middleware
   make:seeder <Name>        Create a new seeder
-  make:resource <Name>      Create model, controller, service, and request
+  make:factory <Model>      Create a model factory with fake data
+  make:command <Name>       Create a console command in app/console/commands
+                            (--command=users:prune, default app:<name>)
+  make:resource <Name> [fields]
+                            Create model, controller, service, and request; with fields
+                            (name:type[:rules]) also the migration, factory and seeder.
+                            Routes are added to routes/api.go unless --no-wire is given
+
+  destroy:resource <Name>   Unwire the routes of a resource and delete its generated files
+  destroy:<type> <Name>     Delete one generated file, e.g. destroy:controller Product
+
+  make:* commands accept --dry-run to print the files instead of writing them and
+  --force to overwrite existing files, showing the diff. They exit with 1 when a file fails.
+  destroy:* only deletes files unchanged since they were generated (.gomen/generated.json)
+  and keeps migrations that already ran; --force deletes them anyway and --dry-run shows
+  what would be deleted.
+  stub:publish              Copy the generator stubs to stubs/ for customizing (--force to reset)
 
 Other Commands:
   list                      Show all available commands
   version                   Show CLI version
-  help                      Show this help message
+  help [command]            Show this help message or the help of a command
 
 Examples:
+  gomen new myapi --module github.com/acme/myapi --db=postgres
   gomen serve
+  gomen serve --port=9000
   gomen migrate
+  gomen migrate --pretend
+  gomen migrate:rollback --step=1
+  gomen migrate:fresh --seed
+  gomen schema:dump --prune
   gomen seed
+  gomen seed --class=ProductSeeder
+  gomen db:fixtures --only=products
+  gomen route:list --method=GET --path=products
+  gomen schedule:list
+  gomen schedule:run
+  gomen make:command DeactivateUsers --command=users:deactivate
   gomen make:controller Product
   gomen make:model Product
   gomen make:migration create_products_table
+  gomen make:factory Product
   gomen make:resource Product
-`)
+  gomen make:resource Product --dry-run
+  gomen make:resource Product name:string:required,max=100 price:decimal stock:int:gte=0 category_id:fk:categories
+  gomen destroy:resource Product --dry-run
+  gomen openapi:generate
+  gomen postman:export --base-url=https://staging.example.com
+  gomen test:api --collection=tests/api.json --junit=reports/api.xml
+  gomen stub:publish
+
+Field types: string, text, int, uint, decimal, float, bool, date, datetime, fk (name:fk:table[:rules])`)
 }
 
 func printCommands() {
 	fmt.Println(`
+Project Commands:
+  new                Create a new project from the embedded skeleton
+
 Application Commands:
   serve              Start the application server
   migrate            Run database migrations
+  migrate:rollback   Roll back the last batch of migrations
+  migrate:status     Show the status of each migration
+  migrate:diff       Generate a migration from model changes
+  migrate:reset      Roll back all migrations
+  migrate:fresh      Drop all tables and re-run all migrations
+  schema:dump        Dump the database schema and migration records
   seed               Run database seeders
+  db:fixtures        Load JSON, YAML and CSV fixtures into the database
+  route:list         List the registered HTTP routes
+  schedule:run       Run the scheduled tasks as they become due
+  schedule:list      List the scheduled tasks and when they run next
+
+Documentation Commands:
+  openapi:generate   Generate the OpenAPI document from the handler annotations
+  postman:export     Export the registered routes as a Postman collection
+
+Testing Commands:
+  test:api           Run a Postman collection as an API test suite
 
 Generator Commands:
   make:controller    Create a new controller
@@ -182,6 +354,10 @@ Generator Commands:
   make:request       Create a new request validation
   make:middleware    Create a new middleware
   make:seeder        Create a new seeder
+  make:factory       Create a model factory with fake data
+  make:command       Create a console command in app/console/commands
   make:resource      Create model, controller, service, and request (full resource)
-`)
+  destroy:resource   Unwire a resource and delete its unchanged generated files
+  destroy:*          Delete an unchanged generated controller, model, migration, ...
+  stub:publish       Copy the generator stubs to stubs/ for customizing`)
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gomen/internal/scaffold"
+)
+
+// newProject creates a project: gomen new <name> [--module=path] [--db=driver]
+func newProject(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Error: Project name is required")
+		fmt.Println("Usage: gomen new <name> [--module=github.com/acme/myapi] [--db=mysql|postgres|sqlite]")
+		os.Exit(1)
+	}
+	name := args[0]
+
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	module := fs.String("module", name, "Go module path of the project")
+	driver := fs.String("db", "mysql", "Database driver: "+strings.Join(scaffold.Drivers, ", "))
+	fs.Parse(args[1:])
+
+	fmt.Printf("\n🚀 Creating project: %s (module %s, %s)\n\n", name, *module, *driver)
+
+	created, err := scaffold.New(scaffold.Options{Name: name, Module: *module, Driver: *driver})
+	if err != nil {
+		fmt.Printf("\033[31m✗\033[0m Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\033[32m✓\033[0m Created %d files in %s\n", len(created), name)
+	fmt.Println("\n✨ Project created successfully!")
+	fmt.Println("\nNext steps:")
+	fmt.Printf("  1. cd %s\n", name)
+	fmt.Println("  2. Check the database settings in .env")
+	fmt.Println("  3. gomen migrate && gomen serve")
+}
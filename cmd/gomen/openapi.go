@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gomen/internal/openapi"
+)
+
+// generateOpenAPI writes the OpenAPI document of the project in the
+// current directory
+func generateOpenAPI(args []string) {
+	fs := flag.NewFlagSet("openapi:generate", flag.ExitOnError)
+	output := fs.String("output", filepath.Join("docs", "openapi.json"), "File to write the document to")
+	fs.Parse(args)
+
+	root, err := os.Getwd()
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	doc, err := openapi.Generate(root)
+	if err != nil {
+		fmt.Printf("\033[31m✗\033[0m Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	// Only document the handlers routes/api.go actually registers
+	if routes, err := appRoutes(); err != nil {
+		fmt.Printf("\033[33m!\033[0m Could not check the @Router annotations against the routes: %s\n", err)
+	} else {
+		for _, operation := range doc.Prune(routes) {
+			fmt.Printf("\033[33m!\033[0m Skipped %s: @Router matches no registered route\n", operation)
+		}
+	}
+
+	if err := doc.Write(*output); err != nil {
+		fmt.Printf("\033[31m✗\033[0m Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	operations := 0
+	for _, item := range doc.Paths {
+		operations += len(item)
+	}
+	fmt.Printf("\033[32m✓\033[0m OpenAPI document written: %s (%d operations, %d schemas)\n",
+		*output, operations, len(doc.Components.Schemas))
+	fmt.Println("  → Served with Swagger UI at /docs when DOCS_ENABLED=true")
+}
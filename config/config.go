@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +12,7 @@ type Config struct {
 	Database DatabaseConfig
 	JWT      JWTConfig
 	CORS     CORSConfig
+	Schedule ScheduleConfig
 }
 
 type AppConfig struct {
@@ -18,15 +20,22 @@ type AppConfig struct {
 	Env   string
 	Port  string
 	Debug bool
+	// Allows migrate:fresh and migrate:reset when Env is production
+	AllowDestructive bool
+	// Serves Swagger UI and the OpenAPI document at /docs
+	Docs bool
 }
 
 type DatabaseConfig struct {
-	Driver   string
-	Host     string
-	Port     string
-	Database string
-	Username string
-	Password string
+	Driver      string
+	Host        string
+	Port        string
+	Database    string
+	Username    string
+	Password    string
+	AutoMigrate bool
+	// Seconds to wait for the migration lock held by another process
+	LockTimeout int
 }
 
 type JWTConfig struct {
@@ -38,6 +47,13 @@ type CORSConfig struct {
 	AllowedOrigins string
 }
 
+type ScheduleConfig struct {
+	// Timezone of the scheduled tasks declared without one, e.g. Asia/Jakarta (default: local time)
+	Timezone string
+	// Runs the scheduler inside serve. Enable it on one replica only.
+	Serve bool
+}
+
 var AppCfg *Config
 
 func Load() {
@@ -46,10 +62,12 @@ func Load() {
 
 	AppCfg = &Config{
 		App: AppConfig{
-			Name:  getEnv("APP_NAME", "GoMen"),
-			Env:   getEnv("APP_ENV", "development"),
-			Port:  getEnv("APP_PORT", "8080"),
-			Debug: getEnv("APP_DEBUG", "true") == "true",
+			Name:             getEnv("APP_NAME", "GoMen"),
+			Env:              getEnv("APP_ENV", "development"),
+			Port:             getEnv("APP_PORT", "8080"),
+			Debug:            getEnv("APP_DEBUG", "true") == "true",
+			AllowDestructive: getEnv("ALLOW_DESTRUCTIVE_COMMANDS", "false") == "true",
+			Docs:             getEnv("DOCS_ENABLED", "false") == "true",
 		},
 		Database: DatabaseConfig{
 			Driver:   getEnv("DB_DRIVER", "mysql"),
@@ -58,6 +76,9 @@ func Load() {
 			Database: getEnv("DB_DATABASE", "go_api"),
 			Username: getEnv("DB_USERNAME", "root"),
 			Password: getEnv("DB_PASSWORD", ""),
+			// Disable once the schema is managed through versioned migrations (migrate:diff)
+			AutoMigrate: getEnv("DB_AUTO_MIGRATE", "true") == "true",
+			LockTimeout: getEnvInt("DB_LOCK_TIMEOUT", 60),
 		},
 		JWT: JWTConfig{
 			Secret:     getEnv("JWT_SECRET", "your-secret-key"),
@@ -66,6 +87,10 @@ func Load() {
 		CORS: CORSConfig{
 			AllowedOrigins: getEnv("ALLOWED_ORIGINS", "http://localhost:3000,http://localhost:8080"),
 		},
+		Schedule: ScheduleConfig{
+			Timezone: getEnv("SCHEDULE_TIMEZONE", ""),
+			Serve:    getEnv("SCHEDULE_IN_SERVE", "false") == "true",
+		},
 	}
 }
 
@@ -76,6 +101,13 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(getEnv(key, "")); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func Get() *Config {
 	return AppCfg
 }
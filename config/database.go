@@ -13,7 +13,7 @@ import (
 
 var DB *gorm.DB
 
-func ConnectDatabase() {
+func ConnectDatabase() error {
 	var err error
 	var dialector gorm.Dialector
 
@@ -44,7 +44,7 @@ func ConnectDatabase() {
 		dialector = sqlite.Open(cfg.Database)
 
 	default:
-		log.Fatal("Unsupported database driver: " + cfg.Driver)
+		return fmt.Errorf("unsupported database driver: %s", cfg.Driver)
 	}
 
 	logLevel := logger.Silent
@@ -57,10 +57,11 @@ func ConnectDatabase() {
 	})
 
 	if err != nil {
-		log.Fatal("Failed to connect to database: " + err.Error())
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	log.Println("Database connected successfully")
+	return nil
 }
 
 func GetDB() *gorm.DB {
@@ -0,0 +1,125 @@
+// Package factories builds models filled with fake data for seeders and tests.
+//
+//	users, err := factories.User().Inactive().Create(db, 3)
+//	product := factories.Product().MakeOne()
+//
+// Each model has a factory file (generated with `gomen make:factory`) that
+// wraps Factory with a definition and named states.
+package factories
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// Factory builds models of type T from a definition, applying states on top
+type Factory[T any] struct {
+	definition func(f *Faker) T
+	states     []func(m *T)
+	sequence   []func(m *T)
+	faker      *Faker
+}
+
+// New returns a factory for the model returned by definition
+func New[T any](definition func(f *Faker) T) *Factory[T] {
+	return &Factory[T]{definition: definition, faker: NewFaker()}
+}
+
+// State returns a copy of the factory that applies fn to every model
+func (f *Factory[T]) State(fn func(m *T)) *Factory[T] {
+	clone := *f
+	clone.states = append(append([]func(m *T){}, f.states...), fn)
+	return &clone
+}
+
+// Sequence returns a copy of the factory that applies states in turn,
+// e.g. alternating between active and inactive users
+func (f *Factory[T]) Sequence(states ...func(m *T)) *Factory[T] {
+	clone := *f
+	clone.sequence = states
+	return &clone
+}
+
+// MakeOne builds a model without saving it
+func (f *Factory[T]) MakeOne() T {
+	f.faker.sequence++
+
+	model := f.definition(f.faker)
+	for _, state := range f.states {
+		state(&model)
+	}
+	if len(f.sequence) > 0 {
+		f.sequence[(f.faker.sequence-1)%len(f.sequence)](&model)
+	}
+
+	return model
+}
+
+// Make builds n models without saving them
+func (f *Factory[T]) Make(n int) []T {
+	models := make([]T, n)
+	for i := range models {
+		models[i] = f.MakeOne()
+	}
+	return models
+}
+
+// CreateOne builds a model and saves it
+func (f *Factory[T]) CreateOne(db *gorm.DB) (T, error) {
+	models, err := f.Create(db, 1)
+	return models[0], err
+}
+
+// Create builds n models and saves them in one batch
+func (f *Factory[T]) Create(db *gorm.DB, n int) ([]T, error) {
+	models := f.Make(n)
+	if n == 0 {
+		return models, nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return models, err
+	}
+
+	// GORM inserts the column default instead of a zero value, which would
+	// turn IsActive=false into true on a `default:true` column
+	zeros := make([]map[string]interface{}, n)
+	for i := range models {
+		value := reflect.ValueOf(&models[i]).Elem()
+		for _, field := range stmt.Schema.Fields {
+			if !field.HasDefaultValue || field.DefaultValueInterface == nil {
+				continue
+			}
+			if _, isZero := field.ValueOf(db.Statement.Context, value); isZero {
+				if zeros[i] == nil {
+					zeros[i] = map[string]interface{}{}
+				}
+				zeros[i][field.DBName] = reflect.Zero(field.FieldType).Interface()
+			}
+		}
+	}
+
+	if err := db.Create(&models).Error; err != nil {
+		return models, err
+	}
+
+	for i := range models {
+		if zeros[i] == nil {
+			continue
+		}
+
+		if err := db.Model(&models[i]).UpdateColumns(zeros[i]).Error; err != nil {
+			return models, err
+		}
+		value := reflect.ValueOf(&models[i]).Elem()
+		for column, zero := range zeros[i] {
+			if err := stmt.Schema.FieldsByDBName[column].Set(db.Statement.Context, value, zero); err != nil {
+				return models, err
+			}
+		}
+	}
+
+	return models, nil
+}
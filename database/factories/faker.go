@@ -0,0 +1,196 @@
+package factories
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	firstNames = []string{
+		"Adi", "Agus", "Andi", "Ayu", "Bayu", "Budi", "Citra", "Dewi", "Dian", "Eka",
+		"Fajar", "Fitri", "Gilang", "Hana", "Indah", "Joko", "Kartika", "Lestari", "Maya", "Nina",
+		"Oki", "Putri", "Rani", "Rizky", "Sari", "Siti", "Taufik", "Wahyu", "Yoga", "Yuni",
+	}
+	lastNames = []string{
+		"Hidayat", "Kusuma", "Lubis", "Nasution", "Pratama", "Purnomo", "Putra", "Rahman", "Saputra", "Setiawan",
+		"Siregar", "Susanto", "Wibowo", "Wijaya", "Santoso", "Gunawan", "Halim", "Hakim", "Permana", "Utami",
+	}
+	words = []string{
+		"alpha", "amet", "aqua", "brisk", "canvas", "cedar", "cloud", "coral", "delta", "ember",
+		"fable", "flint", "garden", "harbor", "indigo", "jade", "kernel", "lumen", "maple", "meadow",
+		"nova", "oasis", "orbit", "pixel", "prism", "quartz", "river", "saffron", "signal", "summit",
+		"tandem", "timber", "umber", "velvet", "vertex", "willow", "zenith", "zephyr", "bamboo", "copper",
+	}
+	emailDomains = []string{"example.com", "example.org", "example.net"}
+)
+
+// Faker generates random fake data. Every factory has its own Faker, and
+// Sequence counts the models that factory has built.
+type Faker struct {
+	rand     *rand.Rand
+	sequence int
+}
+
+// NewFaker returns a Faker seeded from the clock
+func NewFaker() *Faker {
+	return NewFakerWithSeed(time.Now().UnixNano())
+}
+
+// NewFakerWithSeed returns a Faker that always produces the same values for a seed
+func NewFakerWithSeed(seed int64) *Faker {
+	return &Faker{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Sequence returns the 1-based number of the model being built, handy for unique values
+func (f *Faker) Sequence() int {
+	return f.sequence
+}
+
+// Pick returns a random element of items
+func (f *Faker) Pick(items ...string) string {
+	return items[f.rand.Intn(len(items))]
+}
+
+// FirstName returns a random first name
+func (f *Faker) FirstName() string {
+	return f.Pick(firstNames...)
+}
+
+// LastName returns a random last name
+func (f *Faker) LastName() string {
+	return f.Pick(lastNames...)
+}
+
+// Name returns a random full name
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+// Username returns a random lowercase username
+func (f *Faker) Username() string {
+	return strings.ToLower(f.FirstName()) + fmt.Sprintf("%d", f.Int(10, 9999))
+}
+
+// Email returns an email address. The sequence keeps it unique within the
+// factory and the random suffix makes clashes between factories unlikely.
+func (f *Faker) Email() string {
+	return fmt.Sprintf("%s.%s%d%04d@%s", strings.ToLower(f.FirstName()), strings.ToLower(f.LastName()),
+		f.sequence, f.Int(0, 9999), f.Pick(emailDomains...))
+}
+
+// Phone returns a random Indonesian mobile number
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("08%02d%08d", f.Int(11, 99), f.Int(0, 99999999))
+}
+
+// URL returns a random URL
+func (f *Faker) URL() string {
+	return fmt.Sprintf("https://%s.%s/%s", f.Word(), f.Pick(emailDomains...), f.Slug(2))
+}
+
+// Word returns a random word
+func (f *Faker) Word() string {
+	return f.Pick(words...)
+}
+
+// Words returns n random words separated by spaces
+func (f *Faker) Words(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = f.Word()
+	}
+	return strings.Join(parts, " ")
+}
+
+// Title returns a few capitalized words, e.g. for product names
+func (f *Faker) Title() string {
+	parts := strings.Fields(f.Words(f.Int(2, 3)))
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, " ")
+}
+
+// Slug returns n random words joined by dashes
+func (f *Faker) Slug(n int) string {
+	return strings.ReplaceAll(f.Words(n), " ", "-")
+}
+
+// Sentence returns a sentence of 4 to 10 words
+func (f *Faker) Sentence() string {
+	sentence := f.Words(f.Int(4, 10))
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// Paragraph returns 3 to 6 sentences
+func (f *Faker) Paragraph() string {
+	sentences := make([]string, f.Int(3, 6))
+	for i := range sentences {
+		sentences[i] = f.Sentence()
+	}
+	return strings.Join(sentences, " ")
+}
+
+// Text returns sentences up to max characters
+func (f *Faker) Text(max int) string {
+	text := f.Sentence()
+	for {
+		next := text + " " + f.Sentence()
+		if len(next) > max {
+			break
+		}
+		text = next
+	}
+	if len(text) > max {
+		text = text[:max]
+	}
+	return text
+}
+
+// Int returns a random integer between min and max inclusive
+func (f *Faker) Int(min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + f.rand.Intn(max-min+1)
+}
+
+// Float returns a random number between min and max rounded to decimals places
+func (f *Faker) Float(min, max float64, decimals int) float64 {
+	value := min + f.rand.Float64()*(max-min)
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
+
+// Bool returns true with the given chance in percent
+func (f *Faker) Bool(chance int) bool {
+	return f.rand.Intn(100) < chance
+}
+
+// Time returns a random time between from and to
+func (f *Faker) Time(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(f.rand.Int63n(int64(to.Sub(from)))))
+}
+
+// PastTime returns a random time within the last year
+func (f *Faker) PastTime() time.Time {
+	now := time.Now()
+	return f.Time(now.AddDate(-1, 0, 0), now)
+}
+
+// FutureTime returns a random time within the next year
+func (f *Faker) FutureTime() time.Time {
+	now := time.Now()
+	return f.Time(now, now.AddDate(1, 0, 0))
+}
+
+// Date returns a random date (midnight) within the last year
+func (f *Faker) Date() time.Time {
+	return f.PastTime().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,29 @@
+package factories
+
+import (
+	"gomen/app/models"
+)
+
+// ProductFactory builds models.Product
+type ProductFactory struct {
+	*Factory[models.Product]
+}
+
+// Product returns a factory for products
+func Product() *ProductFactory {
+	return &ProductFactory{New(func(f *Faker) models.Product {
+		return models.Product{
+			Name:        f.Title(),
+			Description: f.Paragraph(),
+			Price:       f.Float(1000, 1000000, 2),
+			Stock:       f.Int(0, 100),
+		}
+	})}
+}
+
+// OutOfStock builds products with no stock
+func (f *ProductFactory) OutOfStock() *ProductFactory {
+	return &ProductFactory{f.State(func(p *models.Product) {
+		p.Stock = 0
+	})}
+}
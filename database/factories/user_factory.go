@@ -0,0 +1,47 @@
+package factories
+
+import (
+	"gomen/app/models"
+	"gomen/helpers"
+	"sync"
+)
+
+// DefaultPassword is the plain text password of users built by the user factory
+const DefaultPassword = "password123"
+
+var (
+	hashedPassword     string
+	hashedPasswordOnce sync.Once
+)
+
+// UserFactory builds models.User
+type UserFactory struct {
+	*Factory[models.User]
+}
+
+// User returns a factory for active users with DefaultPassword
+func User() *UserFactory {
+	return &UserFactory{New(func(f *Faker) models.User {
+		return models.User{
+			Name:     f.Name(),
+			Email:    f.Email(),
+			Password: password(),
+			IsActive: true,
+		}
+	})}
+}
+
+// Inactive builds users that cannot log in
+func (f *UserFactory) Inactive() *UserFactory {
+	return &UserFactory{f.State(func(u *models.User) {
+		u.IsActive = false
+	})}
+}
+
+// password hashes DefaultPassword once, bcrypt is too slow to run per user
+func password() string {
+	hashedPasswordOnce.Do(func() {
+		hashedPassword, _ = helpers.HashPassword(DefaultPassword)
+	})
+	return hashedPassword
+}
@@ -0,0 +1,402 @@
+// Package fixtures loads reference data from database/fixtures into the
+// registered models.
+//
+// Each file is named after a table (products.json, users.yaml, categories.csv)
+// and holds a list of records keyed by column or field name. A record is
+// updated when a row with the same natural key exists (see Keys) and
+// created otherwise. A value such as "@categories.Electronics" is replaced
+// by the id of the categories row whose natural key is "Electronics".
+package fixtures
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gomen/app/models"
+	"gomen/config"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Result counts the records loaded from one fixture file
+type Result struct {
+	Table   string
+	File    string
+	Created int
+	Updated int
+}
+
+// fixture is a parsed fixture file
+type fixture struct {
+	table   string
+	file    string
+	records []map[string]interface{}
+}
+
+// Load loads the fixtures in database/fixtures, limited to the only tables when given
+func Load(only []string) error {
+	log.Println("Loading database fixtures...")
+
+	results, err := LoadDir(config.GetDB(), filepath.Join("database", "fixtures"), only)
+	if err != nil {
+		return fmt.Errorf("loading fixtures failed: %w", err)
+	}
+
+	for _, result := range results {
+		log.Printf("Loaded: %s (%d created, %d updated)", result.File, result.Created, result.Updated)
+	}
+
+	log.Println("Database fixtures loaded successfully")
+	return nil
+}
+
+// LoadDir loads the fixture files in dir inside one transaction. only
+// limits loading to the given tables; references to other tables are
+// resolved against rows already in the database.
+func LoadDir(db *gorm.DB, dir string, only []string) ([]Result, error) {
+	fixtures, err := readDir(dir, only)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := sortByReferences(fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := modelSchemas(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, f := range ordered {
+			s, ok := schemas[f.table]
+			if !ok {
+				return fmt.Errorf("%s: no registered model uses table %q", f.file, f.table)
+			}
+
+			result, err := load(tx, s, schemas, f)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+
+	return results, err
+}
+
+func readDir(dir string, only []string) ([]*fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(only))
+	for _, table := range only {
+		wanted[table] = true
+	}
+
+	var fixtures []*fixture
+	seen := map[string]string{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		table := strings.TrimSuffix(entry.Name(), ext)
+		if entry.IsDir() || (len(wanted) > 0 && !wanted[table]) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		var records []map[string]interface{}
+		switch ext {
+		case ".json":
+			records, err = readJSON(path)
+		case ".yaml", ".yml":
+			records, err = readYAML(path)
+		case ".csv":
+			records, err = readCSV(path)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if other, ok := seen[table]; ok {
+			return nil, fmt.Errorf("%s and %s both hold fixtures for %s", other, path, table)
+		}
+		seen[table] = path
+
+		fixtures = append(fixtures, &fixture{table: table, file: path, records: records})
+	}
+
+	for table := range wanted {
+		if _, ok := seen[table]; !ok {
+			return nil, fmt.Errorf("no fixture file for %s in %s", table, dir)
+		}
+	}
+
+	return fixtures, nil
+}
+
+func readJSON(path string) ([]map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	err = json.Unmarshal(content, &records)
+	return records, err
+}
+
+func readYAML(path string) ([]map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	err = yaml.Unmarshal(content, &records)
+	return records, err
+}
+
+// readCSV reads a CSV file with a header row. Empty cells become NULL.
+func readCSV(path string) ([]map[string]interface{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	header := rows[0]
+	records := make([]map[string]interface{}, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			if row[i] == "" {
+				record[column] = nil
+			} else {
+				record[column] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// reference returns the table and key of a "@table.key" value
+func reference(value interface{}) (table, key string, ok bool) {
+	s, isString := value.(string)
+	if !isString || !strings.HasPrefix(s, "@") || strings.HasPrefix(s, "@@") {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(s[1:], ".", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// sortByReferences orders fixtures so referenced tables load first
+func sortByReferences(fixtures []*fixture) ([]*fixture, error) {
+	byTable := make(map[string]*fixture, len(fixtures))
+	for _, f := range fixtures {
+		byTable[f.table] = f
+	}
+
+	var ordered []*fixture
+	state := map[string]int{} // 1 = visiting, 2 = done
+
+	var visit func(f *fixture) error
+	visit = func(f *fixture) error {
+		switch state[f.table] {
+		case 1:
+			return fmt.Errorf("fixtures reference each other in a cycle through %s", f.table)
+		case 2:
+			return nil
+		}
+
+		state[f.table] = 1
+		for _, record := range f.records {
+			for _, value := range record {
+				if table, _, ok := reference(value); ok && table != f.table {
+					if dependency, loaded := byTable[table]; loaded {
+						if err := visit(dependency); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+		state[f.table] = 2
+
+		ordered = append(ordered, f)
+		return nil
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].table < fixtures[j].table })
+	for _, f := range fixtures {
+		if err := visit(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// modelSchemas maps table names to the schemas of the registered models
+func modelSchemas(db *gorm.DB) (map[string]*schema.Schema, error) {
+	schemas := map[string]*schema.Schema{}
+	for _, model := range models.All() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		schemas[stmt.Schema.Table] = stmt.Schema
+	}
+	return schemas, nil
+}
+
+func load(tx *gorm.DB, s *schema.Schema, schemas map[string]*schema.Schema, f *fixture) (Result, error) {
+	result := Result{Table: f.table, File: f.file}
+	key, err := naturalKey(s)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", f.file, err)
+	}
+
+	for i, record := range f.records {
+		model := reflect.New(s.ModelType)
+		var columns []string
+		zeroDefaults := map[string]interface{}{}
+
+		for name, value := range record {
+			field := lookUpField(s, name)
+			if field == nil || field.DBName == "" {
+				return result, fmt.Errorf("%s record %d: unknown column %q", f.file, i+1, name)
+			}
+
+			if table, refKey, ok := reference(value); ok {
+				value, err = resolve(tx, schemas, table, refKey)
+				if err != nil {
+					return result, fmt.Errorf("%s record %d: %w", f.file, i+1, err)
+				}
+			} else if text, ok := value.(string); ok && strings.HasPrefix(text, "@@") {
+				value = text[1:]
+			}
+
+			if err := field.Set(tx.Statement.Context, model.Elem(), value); err != nil {
+				return result, fmt.Errorf("%s record %d: column %s: %w", f.file, i+1, field.DBName, err)
+			}
+
+			columns = append(columns, field.DBName)
+			if _, isZero := field.ValueOf(tx.Statement.Context, model.Elem()); isZero && field.HasDefaultValue {
+				zeroDefaults[field.DBName] = reflect.Zero(field.FieldType).Interface()
+			}
+		}
+
+		conditions := map[string]interface{}{}
+		for _, column := range key {
+			field := s.LookUpField(column)
+			value, isZero := field.ValueOf(tx.Statement.Context, model.Elem())
+			if isZero {
+				conditions = nil
+				break
+			}
+			conditions[column] = value
+		}
+
+		existing := reflect.New(s.ModelType)
+		found := false
+		if conditions != nil {
+			query := tx.Where(conditions).Limit(1).Find(existing.Interface())
+			if query.Error != nil {
+				return result, fmt.Errorf("%s record %d: %w", f.file, i+1, query.Error)
+			}
+			found = query.RowsAffected > 0
+		}
+
+		if found {
+			if err := tx.Model(existing.Interface()).Select(columns).Updates(model.Interface()).Error; err != nil {
+				return result, fmt.Errorf("%s record %d: %w", f.file, i+1, err)
+			}
+			result.Updated++
+			continue
+		}
+
+		if err := tx.Create(model.Interface()).Error; err != nil {
+			return result, fmt.Errorf("%s record %d: %w", f.file, i+1, err)
+		}
+
+		// Create writes the column default instead of zero values (is_active: false)
+		if len(zeroDefaults) > 0 {
+			if err := tx.Model(model.Interface()).UpdateColumns(zeroDefaults).Error; err != nil {
+				return result, fmt.Errorf("%s record %d: %w", f.file, i+1, err)
+			}
+		}
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// lookUpField finds a field by column, Go field or JSON name
+func lookUpField(s *schema.Schema, name string) *schema.Field {
+	if field := s.LookUpField(name); field != nil {
+		return field
+	}
+
+	for _, field := range s.Fields {
+		if strings.Split(field.Tag.Get("json"), ",")[0] == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// resolve returns the primary key of the row in table whose natural key is key
+func resolve(tx *gorm.DB, schemas map[string]*schema.Schema, table, key string) (interface{}, error) {
+	s, ok := schemas[table]
+	if !ok {
+		return nil, fmt.Errorf("reference @%s.%s: no registered model uses table %q", table, key, table)
+	}
+
+	columns, err := naturalKey(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) != 1 {
+		return nil, fmt.Errorf("reference @%s.%s: %s needs a single column natural key", table, key, table)
+	}
+
+	var ids []interface{}
+	if err := tx.Table(table).Where(columns[0]+" = ?", key).Limit(1).
+		Pluck(s.PrioritizedPrimaryField.DBName, &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("reference @%s.%s: no %s row with %s %q", table, key, table, columns[0], key)
+	}
+
+	return ids[0], nil
+}
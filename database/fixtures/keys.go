@@ -0,0 +1,47 @@
+package fixtures
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm/schema"
+)
+
+// Keys maps a table to the columns that identify a fixture record. Tables
+// not listed here use their single column unique index (users.email).
+var Keys = map[string][]string{
+	"products": {"name"},
+}
+
+// naturalKey returns the key columns of a table
+func naturalKey(s *schema.Schema) ([]string, error) {
+	if columns, ok := Keys[s.Table]; ok {
+		for _, column := range columns {
+			if s.LookUpField(column) == nil {
+				return nil, fmt.Errorf("natural key column %s.%s does not exist", s.Table, column)
+			}
+		}
+		return columns, nil
+	}
+
+	for _, field := range s.Fields {
+		if field.Unique {
+			return []string{field.DBName}, nil
+		}
+	}
+
+	indexes := s.ParseIndexes()
+	names := make([]string, 0, len(indexes))
+	for name := range indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if index := indexes[name]; index.Class == "UNIQUE" && len(index.Fields) == 1 {
+			return []string{index.Fields[0].DBName}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("table %s has no unique column, add it to fixtures.Keys", s.Table)
+}
@@ -0,0 +1,70 @@
+// Package guard protects production databases from commands that change
+// or drop data.
+package guard
+
+import (
+	"bufio"
+	"fmt"
+	"gomen/config"
+	"log"
+	"os"
+	"strings"
+)
+
+// Interactive allows Confirm to prompt on a terminal. The scheduler turns
+// it off, so a guarded task in production is refused instead of waiting
+// for an answer.
+var Interactive = true
+
+// Confirm guards a command that changes data (migrate, seed). In production
+// it asks for confirmation unless force is set and returns an error on
+// anything but "yes".
+func Confirm(command string, force bool) error {
+	printTarget(command)
+
+	if config.Get().App.Env != "production" || force {
+		return nil
+	}
+
+	if !Interactive || !isTerminal() {
+		return fmt.Errorf("%s refused: APP_ENV is production. Run it with --force to confirm", command)
+	}
+
+	fmt.Printf("\033[33mAPP_ENV is production.\033[0m Do you really want to run %s? Type \"yes\" to continue: ", command)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+		return fmt.Errorf("%s cancelled", command)
+	}
+	return nil
+}
+
+// ConfirmDestructive guards a command that drops data (migrate:fresh,
+// migrate:reset). In production it refuses unless ALLOW_DESTRUCTIVE_COMMANDS
+// is true, and then still asks like Confirm.
+func ConfirmDestructive(command string, force bool) error {
+	if config.Get().App.Env == "production" && !config.Get().App.AllowDestructive {
+		printTarget(command)
+		return fmt.Errorf("%s refused: it drops data and APP_ENV is production. "+
+			"Set ALLOW_DESTRUCTIVE_COMMANDS=true to allow it", command)
+	}
+
+	return Confirm(command, force)
+}
+
+// printTarget shows which database a command is about to change
+func printTarget(command string) {
+	cfg := config.Get()
+	db := cfg.Database
+
+	target := fmt.Sprintf("%s on %s:%s (%s)", db.Database, db.Host, db.Port, db.Driver)
+	if db.Driver == "sqlite" {
+		target = fmt.Sprintf("%s (sqlite)", db.Database)
+	}
+
+	log.Printf("%s: target database %s, APP_ENV=%s", command, target, cfg.App.Env)
+}
+
+func isTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,313 @@
+// Package lock provides named locks shared by every process connected to
+// the same database, e.g. replicas running migrations during a rolling deploy.
+//
+// MySQL uses GET_LOCK and Postgres uses pg_advisory_lock on a dedicated
+// connection, so the lock is released by the server if the process dies.
+// SQLite has no advisory locks and uses a row in the gomen_locks table
+// instead. A row left by a process of this host that is no longer running
+// is taken over.
+//
+// TryAcquireExpiring uses the table on every dialect, so that a lock held
+// by a process that hangs expires the same way everywhere.
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Table holds the locks taken on SQLite and by TryAcquireExpiring. It is
+// prefixed so it does not clash with a locks table of the application.
+const Table = "gomen_locks"
+
+// pollInterval is how often a held lock is retried while waiting
+const pollInterval = 500 * time.Millisecond
+
+// ErrTimeout is returned by Acquire when the lock is still held after the timeout
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// Lock is a held lock. Call Release when done.
+type Lock struct {
+	Name    string
+	release func() error
+}
+
+// Release gives the lock back
+func (l *Lock) Release() error {
+	return l.release()
+}
+
+// held are the table locks taken by this process, to tell them apart from
+// the rows of an earlier process with the same host and PID, e.g. PID 1 of
+// a restarted container
+var held = struct {
+	sync.Mutex
+	names map[string]bool
+}{names: map[string]bool{}}
+
+// TryAcquire takes the named lock without waiting. ok is false when
+// another process holds it.
+func TryAcquire(db *gorm.DB, name string) (lock *Lock, ok bool, err error) {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return tryAcquireMySQL(db, name)
+	case "postgres":
+		return tryAcquirePostgres(db, name)
+	case "sqlite":
+		return tryAcquireTable(db, name, 0)
+	default:
+		return nil, false, fmt.Errorf("lock: unsupported dialect %q", db.Dialector.Name())
+	}
+}
+
+// TryAcquireExpiring is TryAcquire for locks that may be taken over once
+// they are older than expiry, e.g. the lock of a scheduled task. The lock
+// is a row of Table on every dialect, not an advisory lock, so it is not
+// released when the process dies on MySQL and Postgres either: it expires.
+// A lock of a process of this host that is gone is taken over at once.
+func TryAcquireExpiring(db *gorm.DB, name string, expiry time.Duration) (lock *Lock, ok bool, err error) {
+	switch db.Dialector.Name() {
+	case "mysql", "postgres", "sqlite":
+		return tryAcquireTable(db, name, expiry)
+	default:
+		return nil, false, fmt.Errorf("lock: unsupported dialect %q", db.Dialector.Name())
+	}
+}
+
+// Acquire takes the named lock, waiting up to timeout for another process to release it
+func Acquire(db *gorm.DB, name string, timeout time.Duration) (*Lock, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		lock, ok, err := TryAcquire(db, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return lock, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w %q after %s", ErrTimeout, name, timeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func tryAcquireMySQL(db *gorm.DB, name string) (*Lock, bool, error) {
+	// Locks are server wide, so scope them to the current database.
+	// GET_LOCK names are limited to 64 characters.
+	key := db.Migrator().CurrentDatabase() + ":" + name
+	if len(key) > 64 {
+		key = key[:64]
+	}
+
+	conn, err := dedicatedConn(db)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(context.Background(), "SELECT GET_LOCK(?, 0)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("lock: %w", err)
+	}
+
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return &Lock{Name: name, release: func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", key)
+		return err
+	}}, true, nil
+}
+
+func tryAcquirePostgres(db *gorm.DB, name string) (*Lock, bool, error) {
+	// Advisory locks are keyed by a bigint and scoped to the current database
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	key := int64(hash.Sum64())
+
+	conn, err := dedicatedConn(db)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(context.Background(), "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return &Lock{Name: name, release: func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		return err
+	}}, true, nil
+}
+
+// dedicatedConn takes a connection out of the pool. Advisory locks belong
+// to the session that took them, so lock and unlock must share it.
+func dedicatedConn(db *gorm.DB) (*sql.Conn, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("lock: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("lock: %w", err)
+	}
+
+	return conn, nil
+}
+
+// tryAcquireTable takes the named lock by inserting its row into Table. A
+// zero expiry never expires. acquired_at holds Unix seconds, so the expiry
+// does not depend on the time zone of the connection.
+func tryAcquireTable(db *gorm.DB, name string, expiry time.Duration) (*Lock, bool, error) {
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS " + Table +
+		" (name VARCHAR(255) PRIMARY KEY, owner VARCHAR(255) NOT NULL, acquired_at BIGINT NOT NULL)").Error; err != nil {
+		if isBusy(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("lock: %w", err)
+	}
+
+	if err := clearStale(db, name, expiry); err != nil {
+		if isBusy(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("lock: %w", err)
+	}
+
+	insert := "INSERT INTO " + Table + " (name, owner, acquired_at) VALUES (?, ?, ?) ON CONFLICT (name) DO NOTHING"
+	if db.Dialector.Name() == "mysql" {
+		insert = "INSERT IGNORE INTO " + Table + " (name, owner, acquired_at) VALUES (?, ?, ?)"
+	}
+
+	owner := owner()
+	result := db.Exec(insert, name, owner, time.Now().Unix())
+	if result.Error != nil {
+		if isBusy(result.Error) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("lock: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, false, nil
+	}
+
+	held.Lock()
+	held.names[name] = true
+	held.Unlock()
+
+	return &Lock{Name: name, release: func() error {
+		held.Lock()
+		delete(held.names, name)
+		held.Unlock()
+		return db.Exec("DELETE FROM "+Table+" WHERE name = ? AND owner = ?", name, owner).Error
+	}}, true, nil
+}
+
+// row is a lock in Table
+type row struct {
+	Owner      string
+	AcquiredAt int64
+}
+
+// clearStale deletes the row of the named lock when it is older than expiry
+// or its owner is gone
+func clearStale(db *gorm.DB, name string, expiry time.Duration) error {
+	var current row
+	if err := db.Raw("SELECT owner, acquired_at FROM "+Table+" WHERE name = ?", name).Scan(&current).Error; err != nil {
+		return err
+	}
+	if current.Owner == "" {
+		return nil
+	}
+
+	expired := expiry > 0 && time.Since(time.Unix(current.AcquiredAt, 0)) > expiry
+	if !expired && !abandoned(current.Owner, name) {
+		return nil
+	}
+	return db.Exec("DELETE FROM "+Table+" WHERE name = ? AND owner = ?", name, current.Owner).Error
+}
+
+// abandoned reports whether the owner of the named lock is a process of
+// this host that is no longer running. Other hosts are never checked.
+func abandoned(lockOwner, name string) bool {
+	if lockOwner == owner() {
+		held.Lock()
+		defer held.Unlock()
+		return !held.names[name]
+	}
+
+	i := strings.LastIndexByte(lockOwner, ':')
+	host, _ := os.Hostname()
+	if i < 0 || lockOwner[:i] != host {
+		return false
+	}
+	pid, err := strconv.Atoi(lockOwner[i+1:])
+	if err != nil {
+		return false
+	}
+
+	// Windows cannot send signal 0, so its locks are never taken over
+	if runtime.GOOS == "windows" {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err != nil && !errors.Is(err, syscall.EPERM)
+}
+
+// isBusy reports whether another process is writing to the SQLite
+// database, e.g. running the migrations we wait for
+func isBusy(err error) bool {
+	return strings.Contains(err.Error(), "database is locked")
+}
+
+// owner identifies this process in the lock table
+func owner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// Holder returns who holds the named lock in Table, e.g. to clear a lock
+// left behind by a crashed process. It returns "" for advisory locks.
+func Holder(db *gorm.DB, name string) string {
+	if !db.Migrator().HasTable(Table) {
+		return ""
+	}
+
+	var holder row
+	if err := db.Raw("SELECT owner, acquired_at FROM "+Table+" WHERE name = ?", name).Scan(&holder).Error; err != nil || holder.Owner == "" {
+		return ""
+	}
+	return holder.Owner + " since " + time.Unix(holder.AcquiredAt, 0).Format("2006-01-02 15:04:05")
+}
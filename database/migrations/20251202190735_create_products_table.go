@@ -7,6 +7,10 @@ import (
 // CreateProductsTable migration
 type CreateProductsTable struct{}
 
+func init() {
+	Register("20251202190735_create_products_table", &CreateProductsTable{})
+}
+
 func (m *CreateProductsTable) Up(db *gorm.DB) error {
 	// Example: Create table
 	// return db.Exec(`
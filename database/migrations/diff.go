@@ -0,0 +1,443 @@
+package migrations
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gomen/database/schema"
+
+	"gorm.io/gorm"
+	gormschema "gorm.io/gorm/schema"
+)
+
+// TableDiff lists the changes needed to bring a table in line with its model
+type TableDiff struct {
+	Table       string
+	Model       string
+	Create      bool
+	Fields      []*gormschema.Field
+	AddColumns  []*gormschema.Field
+	DropColumns []gorm.ColumnType
+	AddIndexes  []gormschema.Index
+	DropIndexes []indexInfo
+}
+
+// indexInfo is a database index as reported by introspection
+type indexInfo struct {
+	name    string
+	columns []string
+	unique  bool
+}
+
+// Diff compares the GORM schema of models against the live database
+func Diff(db *gorm.DB, models ...interface{}) ([]TableDiff, error) {
+	var diffs []TableDiff
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+
+		diff := TableDiff{Table: stmt.Table, Model: stmt.Schema.Name}
+		modelIndexes := sortedIndexes(stmt.Schema)
+
+		if !db.Migrator().HasTable(stmt.Table) {
+			diff.Create = true
+			diff.Fields = columnFields(stmt.Schema)
+			diff.AddIndexes = modelIndexes
+			diffs = append(diffs, diff)
+			continue
+		}
+
+		columnTypes, err := db.Migrator().ColumnTypes(stmt.Table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", stmt.Table, err)
+		}
+
+		existing := make(map[string]bool, len(columnTypes))
+		for _, column := range columnTypes {
+			existing[column.Name()] = true
+		}
+
+		for _, field := range columnFields(stmt.Schema) {
+			if !existing[field.DBName] {
+				diff.AddColumns = append(diff.AddColumns, field)
+			}
+		}
+
+		for _, column := range columnTypes {
+			if stmt.Schema.LookUpField(column.Name()) == nil {
+				diff.DropColumns = append(diff.DropColumns, column)
+			}
+		}
+
+		indexes, err := indexesOf(db, stmt.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		existingIndexes := make(map[string]bool, len(indexes))
+		for _, index := range indexes {
+			existingIndexes[index.name] = true
+		}
+
+		wanted := make(map[string]bool, len(modelIndexes))
+		for _, index := range modelIndexes {
+			wanted[index.Name] = true
+			if !existingIndexes[index.Name] {
+				diff.AddIndexes = append(diff.AddIndexes, index)
+			}
+		}
+
+		for _, index := range indexes {
+			// Unique columns are backed by an index the model does not name
+			if index.unique && len(index.columns) == 1 {
+				if field := stmt.Schema.LookUpField(index.columns[0]); field != nil && field.Unique {
+					continue
+				}
+			}
+
+			if !wanted[index.name] {
+				diff.DropIndexes = append(diff.DropIndexes, index)
+			}
+		}
+
+		if len(diff.AddColumns)+len(diff.DropColumns)+len(diff.AddIndexes)+len(diff.DropIndexes) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+// WriteDiffMigration renders diffs as a migration file in dir and returns its path
+func WriteDiffMigration(dir, name string, diffs []TableDiff) (string, error) {
+	timestamp := time.Now().Format("20060102150405")
+	fileName := timestamp + "_" + name
+	structName := pascalCase(name)
+
+	var up, down strings.Builder
+	for _, diff := range diffs {
+		writeUp(&up, diff)
+	}
+	for i := len(diffs) - 1; i >= 0; i-- {
+		writeDown(&down, diffs[i])
+	}
+
+	source := fmt.Sprintf(`package migrations
+
+import (
+	%q
+
+	"gorm.io/gorm"
+)
+
+// %s migration (generated by migrate:diff)
+type %s struct{}
+
+func init() {
+	Register(%q, &%s{})
+}
+
+func (m *%s) Up(db *gorm.DB) error {
+%s
+	return nil
+}
+
+func (m *%s) Down(db *gorm.DB) error {
+%s
+	return nil
+}
+`, reflect.TypeOf(schema.Blueprint{}).PkgPath(),
+		structName, structName,
+		fileName, structName,
+		structName, up.String(),
+		structName, down.String())
+
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return "", fmt.Errorf("failed to format migration: %w", err)
+	}
+
+	path := filepath.Join(dir, fileName+".go")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("file already exists: %s", path)
+	}
+
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		return "", fmt.Errorf("failed to write migration: %w", err)
+	}
+
+	return path, nil
+}
+
+func writeUp(b *strings.Builder, diff TableDiff) {
+	if diff.Create {
+		fmt.Fprintf(b, "if err := schema.Create(db, %q, func(t *schema.Blueprint) {\n", diff.Table)
+		indexed := uniqueIndexed(diff.AddIndexes)
+		for _, field := range diff.Fields {
+			b.WriteString(columnCall(field, indexed) + "\n")
+		}
+		for _, index := range diff.AddIndexes {
+			b.WriteString(indexCall(index.Name, indexColumns(index), index.Class == "UNIQUE") + "\n")
+		}
+		b.WriteString("}); err != nil {\nreturn err\n}\n\n")
+		return
+	}
+
+	fmt.Fprintf(b, "if err := schema.Table(db, %q, func(t *schema.Blueprint) {\n", diff.Table)
+	if len(diff.AddColumns) == 1 && len(diff.DropColumns) == 1 {
+		fmt.Fprintf(b, "// If %s was renamed to %s, replace the add/drop pair with\n// t.RenameColumn(%q, %q)\n",
+			diff.DropColumns[0].Name(), diff.AddColumns[0].DBName, diff.DropColumns[0].Name(), diff.AddColumns[0].DBName)
+	}
+	indexed := uniqueIndexed(diff.AddIndexes)
+	for _, field := range diff.AddColumns {
+		b.WriteString(columnCall(field, indexed) + "\n")
+	}
+	for _, index := range diff.AddIndexes {
+		b.WriteString(indexCall(index.Name, indexColumns(index), index.Class == "UNIQUE") + "\n")
+	}
+	for _, index := range diff.DropIndexes {
+		fmt.Fprintf(b, "t.DropIndex(%q)\n", index.name)
+	}
+	for _, column := range diff.DropColumns {
+		fmt.Fprintf(b, "t.DropColumn(%q)\n", column.Name())
+	}
+	b.WriteString("}); err != nil {\nreturn err\n}\n\n")
+}
+
+func writeDown(b *strings.Builder, diff TableDiff) {
+	if diff.Create {
+		fmt.Fprintf(b, "if err := schema.DropIfExists(db, %q); err != nil {\nreturn err\n}\n\n", diff.Table)
+		return
+	}
+
+	fmt.Fprintf(b, "if err := schema.Table(db, %q, func(t *schema.Blueprint) {\n", diff.Table)
+	for _, column := range diff.DropColumns {
+		b.WriteString(restoreColumnCall(column) + "\n")
+	}
+	for _, index := range diff.DropIndexes {
+		b.WriteString(indexCall(index.name, index.columns, index.unique) + "\n")
+	}
+	for _, index := range diff.AddIndexes {
+		fmt.Fprintf(b, "t.DropIndex(%q)\n", index.Name)
+	}
+	for _, field := range diff.AddColumns {
+		fmt.Fprintf(b, "t.DropColumn(%q)\n", field.DBName)
+	}
+	b.WriteString("}); err != nil {\nreturn err\n}\n\n")
+}
+
+// columnCall renders a schema.Blueprint call for a model field. Columns in
+// indexed get their unique index from the named index call instead.
+func columnCall(field *gormschema.Field, indexed map[string]bool) string {
+	if field.PrimaryKey && field.AutoIncrement {
+		if field.DBName == "id" {
+			return "t.ID()"
+		}
+		return fmt.Sprintf("t.BigIncrements(%q)", field.DBName)
+	}
+
+	var call string
+	switch field.DataType {
+	case gormschema.Bool:
+		call = fmt.Sprintf("t.Boolean(%q)", field.DBName)
+	case gormschema.Int, gormschema.Uint:
+		switch {
+		case field.Size > 0 && field.Size <= 16:
+			call = fmt.Sprintf("t.SmallInteger(%q)", field.DBName)
+		case field.Size > 0 && field.Size <= 32:
+			call = fmt.Sprintf("t.Integer(%q)", field.DBName)
+		default:
+			call = fmt.Sprintf("t.BigInteger(%q)", field.DBName)
+		}
+		if field.DataType == gormschema.Uint {
+			call += ".Unsigned()"
+		}
+	case gormschema.Float:
+		switch {
+		case field.Precision > 0:
+			call = fmt.Sprintf("t.Decimal(%q, %d, %d)", field.DBName, field.Precision, field.Scale)
+		case field.Size == 32:
+			call = fmt.Sprintf("t.Float(%q)", field.DBName)
+		default:
+			call = fmt.Sprintf("t.Double(%q)", field.DBName)
+		}
+	case gormschema.String:
+		if field.Size > 0 {
+			call = fmt.Sprintf("t.String(%q, %d)", field.DBName, field.Size)
+		} else {
+			call = fmt.Sprintf("t.Text(%q)", field.DBName)
+		}
+	case gormschema.Time:
+		call = fmt.Sprintf("t.DateTime(%q)", field.DBName)
+	case "text":
+		call = fmt.Sprintf("t.Text(%q)", field.DBName)
+	default:
+		call = fmt.Sprintf("t.Column(%q, %q)", field.DBName, string(field.DataType))
+	}
+
+	if !field.NotNull && !field.PrimaryKey {
+		call += ".Nullable()"
+	}
+
+	if field.HasDefaultValue && field.DefaultValue != "" {
+		if field.DefaultValueInterface != nil {
+			call += fmt.Sprintf(".Default(%#v)", field.DefaultValueInterface)
+		} else {
+			call += fmt.Sprintf(".Default(schema.Expression(%q))", field.DefaultValue)
+		}
+	}
+
+	if field.Unique && !indexed[field.DBName] {
+		call += ".Unique()"
+	}
+
+	return call
+}
+
+// restoreColumnCall renders a call that re-creates a dropped column as it
+// exists in the database today
+func restoreColumnCall(column gorm.ColumnType) string {
+	sqlType, ok := column.ColumnType()
+	if !ok || sqlType == "" {
+		sqlType = strings.ToLower(column.DatabaseTypeName())
+		if length, ok := column.Length(); ok && length > 0 {
+			sqlType = fmt.Sprintf("%s(%d)", sqlType, length)
+		}
+	}
+
+	call := fmt.Sprintf("t.Column(%q, %q)", column.Name(), sqlType)
+
+	if nullable, ok := column.Nullable(); !ok || nullable {
+		call += ".Nullable()"
+	}
+
+	if value, ok := column.DefaultValue(); ok && value != "" {
+		call += fmt.Sprintf(".Default(schema.Expression(%q))", value)
+	}
+
+	return call
+}
+
+func indexCall(name string, columns []string, unique bool) string {
+	method := "Index"
+	if unique {
+		method = "Unique"
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = fmt.Sprintf("%q", column)
+	}
+
+	return fmt.Sprintf("t.%s(%s).Name(%q)", method, strings.Join(quoted, ", "), name)
+}
+
+func columnFields(s *gormschema.Schema) []*gormschema.Field {
+	var fields []*gormschema.Field
+	for _, field := range s.Fields {
+		if field.DBName != "" && !field.IgnoreMigration {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func sortedIndexes(s *gormschema.Schema) []gormschema.Index {
+	var indexes []gormschema.Index
+	for _, index := range s.ParseIndexes() {
+		indexes = append(indexes, index)
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	return indexes
+}
+
+// uniqueIndexed returns the columns with a single column unique index.
+// ParseIndexes marks their fields Unique, so a uniqueIndex tag would
+// otherwise create the index twice: once unnamed with the column and once
+// under the name GORM gives it.
+func uniqueIndexed(indexes []gormschema.Index) map[string]bool {
+	indexed := map[string]bool{}
+	for _, index := range indexes {
+		if index.Class == "UNIQUE" && len(index.Fields) == 1 {
+			indexed[index.Fields[0].DBName] = true
+		}
+	}
+	return indexed
+}
+
+func indexColumns(index gormschema.Index) []string {
+	columns := make([]string, len(index.Fields))
+	for i, option := range index.Fields {
+		columns[i] = option.DBName
+	}
+	return columns
+}
+
+// indexesOf lists the secondary indexes of a table. The sqlite driver does
+// not implement Migrator().GetIndexes, so sqlite_master is read directly.
+func indexesOf(db *gorm.DB, table string) ([]indexInfo, error) {
+	var indexes []indexInfo
+
+	if db.Dialector.Name() == "sqlite" {
+		var rows []struct {
+			Name string
+			SQL  string
+		}
+		if err := db.Raw("SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table).
+			Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to read indexes of %s: %w", table, err)
+		}
+
+		for _, row := range rows {
+			var columns []string
+			if err := db.Raw("SELECT name FROM pragma_index_info(?) ORDER BY seqno", row.Name).
+				Scan(&columns).Error; err != nil {
+				return nil, fmt.Errorf("failed to read index %s: %w", row.Name, err)
+			}
+
+			unique := strings.HasPrefix(strings.ToUpper(row.SQL), "CREATE UNIQUE")
+			indexes = append(indexes, indexInfo{name: row.Name, columns: columns, unique: unique})
+		}
+
+		return indexes, nil
+	}
+
+	found, err := db.Migrator().GetIndexes(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes of %s: %w", table, err)
+	}
+
+	for _, index := range found {
+		if primary, _ := index.PrimaryKey(); primary {
+			continue
+		}
+
+		unique, _ := index.Unique()
+		indexes = append(indexes, indexInfo{name: index.Name(), columns: index.Columns(), unique: unique})
+	}
+
+	return indexes, nil
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(s, "_") {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,263 @@
+package migrations
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gomen/config"
+	"gomen/database/lock"
+
+	"gorm.io/gorm"
+)
+
+// SchemaPath returns the schema dump file for a driver,
+// e.g. database/schema/mysql-schema.sql
+func SchemaPath(driver string) string {
+	return filepath.Join("database", "schema", driver+"-schema.sql")
+}
+
+var (
+	autoIncrementPattern = regexp.MustCompile(` AUTO_INCREMENT=\d+`)
+	dollarTagPattern     = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+)
+
+// DumpSchema writes the structure of every table plus the rows of
+// schema_migrations to path. Postgres dumps need pg_dump on the PATH.
+func DumpSchema(db *gorm.DB, cfg config.DatabaseConfig, path string) error {
+	var structure string
+	var err error
+
+	switch db.Dialector.Name() {
+	case "mysql":
+		structure, err = dumpMySQL(db)
+	case "postgres":
+		structure, err = dumpPostgres(cfg)
+	case "sqlite":
+		structure, err = dumpSQLite(db)
+	default:
+		err = fmt.Errorf("unsupported dialect %q", db.Dialector.Name())
+	}
+	if err != nil {
+		return err
+	}
+
+	var records []SchemaMigration
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		if err := db.Order("id").Find(&records).Error; err != nil {
+			return fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(structure)
+	if db.Dialector.Name() == "postgres" && len(records) > 0 {
+		// pg_dump empties search_path, which would hide schema_migrations from the inserts
+		b.WriteString("RESET search_path;\n\n")
+	}
+	for _, record := range records {
+		fmt.Fprintf(&b, "INSERT INTO schema_migrations (migration, batch, created_at) VALUES ('%s', %d, CURRENT_TIMESTAMP);\n",
+			strings.ReplaceAll(record.Migration, "'", "''"), record.Batch)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+func dumpMySQL(db *gorm.DB) (string, error) {
+	var tables []string
+	if err := db.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name").
+		Scan(&tables).Error; err != nil {
+		return "", fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("SET FOREIGN_KEY_CHECKS = 0;\n\n")
+	for _, table := range tables {
+		if table == lock.Table {
+			continue
+		}
+		var name, create string
+		if err := db.Raw("SHOW CREATE TABLE `"+table+"`").Row().Scan(&name, &create); err != nil {
+			return "", fmt.Errorf("failed to dump table %s: %w", table, err)
+		}
+		b.WriteString(autoIncrementPattern.ReplaceAllString(create, "") + ";\n\n")
+	}
+	b.WriteString("SET FOREIGN_KEY_CHECKS = 1;\n\n")
+
+	return b.String(), nil
+}
+
+func dumpPostgres(cfg config.DatabaseConfig) (string, error) {
+	cmd := exec.Command("pg_dump", "--schema-only", "--no-owner", "--no-privileges", "--exclude-table="+lock.Table,
+		"--host", cfg.Host, "--port", cfg.Port, "--username", cfg.Username, cfg.Database)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+cfg.Password)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("pg_dump failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(output) + "\n", nil
+}
+
+func dumpSQLite(db *gorm.DB) (string, error) {
+	var statements []string
+	if err := db.Raw(`SELECT sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' AND tbl_name <> ?
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, name`, lock.Table).
+		Scan(&statements).Error; err != nil {
+		return "", fmt.Errorf("failed to read sqlite_master: %w", err)
+	}
+
+	var b strings.Builder
+	for _, statement := range statements {
+		b.WriteString(statement + ";\n\n")
+	}
+
+	return b.String(), nil
+}
+
+// LoadSchema runs every statement of a schema dump. The statements share
+// one connection so session settings such as FOREIGN_KEY_CHECKS apply. On
+// Postgres and SQLite they run in a transaction, so a failing statement
+// leaves the database empty instead of with a partial schema that migrate
+// would take as loaded. MySQL commits every DDL statement implicitly.
+func LoadSchema(db *gorm.DB, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	load := db.Connection
+	if name := db.Dialector.Name(); name == "postgres" || name == "sqlite" {
+		load = func(fn func(tx *gorm.DB) error) error {
+			return db.Transaction(fn)
+		}
+	}
+
+	return load(func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(string(content)) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to load %s: %w", path, err)
+			}
+		}
+
+		// pg_dump empties search_path for the session; hand the connection back clean
+		if tx.Dialector.Name() == "postgres" {
+			return tx.Exec("RESET ALL").Error
+		}
+		return nil
+	})
+}
+
+// splitStatements splits SQL on semicolons outside of quotes, comments and
+// Postgres dollar-quoted bodies. psql meta-commands (lines starting with a
+// backslash) are skipped.
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote byte
+	var dollarTag string
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+
+		switch {
+		case dollarTag != "":
+			if strings.HasPrefix(sql[i:], dollarTag) {
+				current.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				dollarTag = ""
+				continue
+			}
+
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+
+		case c == '$':
+			if tag := dollarTagPattern.FindString(sql[i:]); tag != "" {
+				current.WriteString(tag)
+				i += len(tag) - 1
+				dollarTag = tag
+				continue
+			}
+
+		case c == '-' && strings.HasPrefix(sql[i:], "--"), c == '\\' && (i == 0 || sql[i-1] == '\n'):
+			// Skip to the end of the line, keeping the line break so the
+			// words around the comment stay apart
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end - 1
+			} else {
+				i = len(sql)
+			}
+			continue
+
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(sql)
+			}
+			current.WriteByte(' ')
+			continue
+
+		case c == ';':
+			flush()
+			continue
+		}
+
+		current.WriteByte(c)
+	}
+	flush()
+
+	return statements
+}
+
+// PruneMigrations deletes the files of migrations recorded in the schema
+// dump, since loading the dump replaces them. It returns the deleted paths.
+func PruneMigrations(db *gorm.DB, dir string) ([]string, error) {
+	ran, err := NewMigrator(db, migrations).ran()
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for name := range ran {
+		path := filepath.Join(dir, name+".go")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, path)
+	}
+
+	sort.Strings(pruned)
+	return pruned, nil
+}
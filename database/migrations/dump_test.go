@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"only comments", "-- nothing here\n/* or here */\n", nil},
+		{"statements", "CREATE TABLE a (id int);\nCREATE TABLE b (id int);\n", []string{"CREATE TABLE a (id int)", "CREATE TABLE b (id int)"}},
+		{"no trailing semicolon", "SELECT 1;\nSELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"empty statements", ";;\n;SELECT 1;;", []string{"SELECT 1"}},
+		{"line comment", "-- users; and more\nCREATE TABLE users (id int); -- done;\n", []string{"CREATE TABLE users (id int)"}},
+		{"line comment between words", "SELECT 1--one;\nFROM dual;", []string{"SELECT 1\nFROM dual"}},
+		{"block comment", "/* a; b */CREATE TABLE a (id int);", []string{"CREATE TABLE a (id int)"}},
+		{"block comment between words", "SELECT/*;*/1;", []string{"SELECT 1"}},
+		{"unterminated block comment", "SELECT 1; /* never closed;", []string{"SELECT 1"}},
+		{"single quotes", "INSERT INTO t VALUES ('a;b');", []string{"INSERT INTO t VALUES ('a;b')"}},
+		{"doubled quotes", "INSERT INTO t VALUES ('it''s; fine');SELECT 1;", []string{"INSERT INTO t VALUES ('it''s; fine')", "SELECT 1"}},
+		{"comment markers in strings", "INSERT INTO t VALUES ('-- no', '/* no */');", []string{"INSERT INTO t VALUES ('-- no', '/* no */')"}},
+		{"double quoted identifier", `CREATE TABLE "a;b" (id int);`, []string{`CREATE TABLE "a;b" (id int)`}},
+		{"backtick identifier", "CREATE TABLE `a;b` (id int);", []string{"CREATE TABLE `a;b` (id int)"}},
+		{
+			"dollar quotes",
+			"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql;\nSELECT f();",
+			[]string{"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql", "SELECT f()"},
+		},
+		{
+			"tagged dollar quotes",
+			"DO $body$ BEGIN PERFORM '$$;'; END $body$;",
+			[]string{"DO $body$ BEGIN PERFORM '$$;'; END $body$"},
+		},
+		{
+			"dollar tag with digits",
+			"DO $fn1$ BEGIN NULL; END $fn1$;",
+			[]string{"DO $fn1$ BEGIN NULL; END $fn1$"},
+		},
+		{"positional parameter", "PREPARE p AS SELECT $1; EXECUTE p(1);", []string{"PREPARE p AS SELECT $1", "EXECUTE p(1)"}},
+		{
+			"psql meta-commands",
+			"\\restrict abc\nSET x = 1;\n\\unrestrict abc\n",
+			[]string{"SET x = 1"},
+		},
+		{"backslash inside a line", "SELECT 'a\\b';", []string{"SELECT 'a\\b'"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) =\n%q\nwant\n%q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
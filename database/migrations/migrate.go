@@ -1,24 +1,299 @@
 package migrations
 
 import (
+	"fmt"
 	"gomen/app/models"
 	"gomen/config"
+	"gomen/database/lock"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
 )
 
-func Migrate() {
+// lockName is the lock held while migrations change the schema
+const lockName = "migrations"
+
+// Migrate loads the stored schema into a fresh database, runs the pending
+// migrations and then AutoMigrate
+func Migrate() error {
+	return withLock(migrate)
+}
+
+func migrate() error {
 	db := config.GetDB()
 
 	log.Println("Running database migrations...")
 
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.Product{},
-	)
+	// A fresh database starts from the schema dump, if there is one
+	schemaPath := SchemaPath(config.Get().Database.Driver)
+	if _, err := os.Stat(schemaPath); err == nil && !db.Migrator().HasTable(&SchemaMigration{}) {
+		if err := LoadSchema(db, schemaPath); err != nil {
+			return err
+		}
+		log.Println("Loaded stored database schema: " + schemaPath)
+	}
+
+	// Migrations run before AutoMigrate so the tables they create keep their
+	// exact definition; AutoMigrate then only adds what the models declare on top
+	ran, err := NewMigrator(db, migrations).Run()
+	logNames("Migrated", ran)
 
 	if err != nil {
-		log.Fatal("Migration failed: " + err.Error())
+		return err
+	}
+
+	if config.Get().Database.AutoMigrate {
+		if err := db.AutoMigrate(models.All()...); err != nil {
+			return err
+		}
+	}
+
+	if len(ran) == 0 {
+		log.Println("Nothing to migrate")
 	}
 
 	log.Println("Database migrations completed successfully")
+	return nil
+}
+
+// Pretend prints the SQL Migrate would run without executing it
+func Pretend() error {
+	db := config.GetDB()
+	migrator := NewMigrator(db, migrations)
+
+	schemaPath := SchemaPath(config.Get().Database.Driver)
+	if _, err := os.Stat(schemaPath); err == nil && !db.Migrator().HasTable(&SchemaMigration{}) {
+		fmt.Printf("The stored schema %s would be loaded first; the output below ignores it\n\n", schemaPath)
+	}
+
+	pretended, err := migrator.PretendRun()
+	for _, migration := range pretended {
+		printPretended(migration)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(pretended) == 0 {
+		fmt.Println("Nothing to migrate")
+	}
+
+	// Compared against the current database, so tables created by the
+	// migrations above show up here as well
+	if config.Get().Database.AutoMigrate {
+		statements, err := migrator.PretendAutoMigrate(models.All()...)
+		printPretended(PretendedMigration{Name: "AutoMigrate (registered models)", Statements: statements})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PretendRollback prints the SQL Rollback(steps) would run without executing it
+func PretendRollback(steps int) error {
+	pretended, err := NewMigrator(config.GetDB(), migrations).PretendRollback(steps)
+	for _, migration := range pretended {
+		printPretended(migration)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(pretended) == 0 {
+		fmt.Println("Nothing to roll back")
+	}
+	return nil
+}
+
+// Rollback reverts the last batch, or the last steps migrations when steps > 0
+func Rollback(steps int) error {
+	return withLock(func() error {
+		log.Println("Rolling back database migrations...")
+
+		rolledBack, err := NewMigrator(config.GetDB(), migrations).Rollback(steps)
+		logNames("Rolled back", rolledBack)
+
+		if err != nil {
+			return err
+		}
+
+		if len(rolledBack) == 0 {
+			log.Println("Nothing to roll back")
+		}
+		return nil
+	})
+}
+
+// Reset reverts every migration that has been run
+func Reset() error {
+	return withLock(func() error {
+		log.Println("Resetting database migrations...")
+
+		rolledBack, err := NewMigrator(config.GetDB(), migrations).Reset()
+		logNames("Rolled back", rolledBack)
+
+		if err != nil {
+			return err
+		}
+
+		if len(rolledBack) == 0 {
+			log.Println("Nothing to reset")
+		}
+		return nil
+	})
+}
+
+// Fresh drops every table and runs all migrations from scratch
+func Fresh() error {
+	return withLock(func() error {
+		log.Println("Dropping all tables...")
+
+		if err := NewMigrator(config.GetDB(), migrations).DropAllTables(); err != nil {
+			return err
+		}
+
+		return migrate()
+	})
+}
+
+// withLock runs fn while holding the migration lock, so replicas started
+// together during a deploy migrate one at a time. The lock is released
+// before the error of fn is returned.
+func withLock(fn func() error) error {
+	db := config.GetDB()
+	timeout := time.Duration(config.Get().Database.LockTimeout) * time.Second
+
+	l, ok, err := lock.TryAcquire(db, lockName)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	if !ok {
+		log.Printf("Waiting for the migration lock held by another process (timeout %s)...", timeout)
+
+		l, err = lock.Acquire(db, lockName, timeout)
+		if err != nil {
+			if holder := lock.Holder(db, lockName); holder != "" {
+				log.Printf("Migration lock held by %s. If that process on another host is gone, delete the %q row from the %s table.",
+					holder, lockName, lock.Table)
+			}
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		log.Println("Migration lock acquired")
+	}
+
+	err = fn()
+
+	if releaseErr := l.Release(); releaseErr != nil {
+		log.Println("Failed to release migration lock: " + releaseErr.Error())
+	}
+
+	return err
+}
+
+// GenerateDiff writes a migration with the changes needed to bring the
+// database in line with the registered models
+func GenerateDiff(name string) error {
+	diffs, err := Diff(config.GetDB(), models.All()...)
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		log.Println("No changes detected")
+		return nil
+	}
+
+	path, err := WriteDiffMigration(filepath.Join("database", "migrations"), name, diffs)
+	if err != nil {
+		return err
+	}
+
+	for _, diff := range diffs {
+		if diff.Create {
+			log.Printf("Create table %s", diff.Table)
+			continue
+		}
+		log.Printf("Alter table %s: +%d/-%d columns, +%d/-%d indexes", diff.Table,
+			len(diff.AddColumns), len(diff.DropColumns), len(diff.AddIndexes), len(diff.DropIndexes))
+	}
+
+	log.Println("Migration created: " + path)
+	return nil
+}
+
+// Dump writes the current schema and migration records to the schema dump
+// file. With prune the migration files covered by the dump are deleted.
+func Dump(prune bool) error {
+	db := config.GetDB()
+	cfg := config.Get().Database
+	path := SchemaPath(cfg.Driver)
+
+	if err := DumpSchema(db, cfg, path); err != nil {
+		return fmt.Errorf("schema dump failed: %w", err)
+	}
+	log.Println("Database schema dumped: " + path)
+
+	if !prune {
+		return nil
+	}
+
+	pruned, err := PruneMigrations(db, filepath.Join("database", "migrations"))
+	logNames("Pruned", pruned)
+	if err != nil {
+		return fmt.Errorf("prune failed: %w", err)
+	}
+	return nil
+}
+
+// Status prints a table of ran and pending migrations
+func Status() error {
+	statuses, err := Statuses()
+	if err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No migrations found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Migration\tStatus\tBatch")
+	for _, status := range statuses {
+		if status.Ran {
+			fmt.Fprintf(w, "%s\tRan\t%d\n", status.Name, status.Batch)
+		} else {
+			fmt.Fprintf(w, "%s\tPending\t-\n", status.Name)
+		}
+	}
+	return w.Flush()
+}
+
+// Statuses returns the status of the registered migrations and of the
+// recorded ones whose file is gone
+func Statuses() ([]MigrationStatus, error) {
+	return NewMigrator(config.GetDB(), migrations).Status()
+}
+
+func printPretended(migration PretendedMigration) {
+	fmt.Printf("\033[33m%s\033[0m\n", migration.Name)
+	if len(migration.Statements) == 0 {
+		fmt.Println("  -- no statements")
+	}
+	for _, statement := range migration.Statements {
+		fmt.Println("  " + strings.ReplaceAll(statement, "\n", "\n  ") + ";")
+	}
+	fmt.Println()
+}
+
+func logNames(action string, names []string) {
+	for _, name := range names {
+		log.Println(action + ": " + name)
+	}
 }
@@ -0,0 +1,333 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gomen/database/lock"
+
+	"gorm.io/gorm"
+)
+
+// Migration is implemented by every versioned migration in this package
+type Migration interface {
+	Up(db *gorm.DB) error
+	Down(db *gorm.DB) error
+}
+
+// Entry pairs a migration with its timestamped file name
+// (e.g. 20251202190735_create_products_table)
+type Entry struct {
+	Name      string
+	Migration Migration
+}
+
+// MigrationStatus describes whether a migration has been run and in which batch
+type MigrationStatus struct {
+	Name  string `json:"name"`
+	Ran   bool   `json:"ran"`
+	Batch int    `json:"batch"`
+}
+
+// SchemaMigration is a row of the schema_migrations table
+type SchemaMigration struct {
+	ID        uint      `gorm:"primaryKey"`
+	Migration string    `gorm:"size:255;uniqueIndex;not null"`
+	Batch     int       `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator runs versioned migrations and records them in schema_migrations
+type Migrator struct {
+	db      *gorm.DB
+	entries []Entry
+}
+
+func NewMigrator(db *gorm.DB, entries []Entry) *Migrator {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+
+	// File names start with a timestamp, so sorting by name sorts by time
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return &Migrator{db: db, entries: sorted}
+}
+
+// Pending returns the migrations that have not been run yet, oldest first
+func (m *Migrator) Pending() ([]Entry, error) {
+	ran, err := m.ran()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Entry
+	for _, entry := range m.entries {
+		if _, ok := ran[entry.Name]; !ok {
+			pending = append(pending, entry)
+		}
+	}
+
+	return pending, nil
+}
+
+// Run executes every pending migration as a single new batch and returns
+// the names of the migrations that were run
+func (m *Migrator) Run() ([]string, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+
+	batch, err := m.lastBatch()
+	if err != nil {
+		return nil, err
+	}
+	batch++
+
+	var names []string
+	for _, entry := range pending {
+		if err := m.runUp(entry, batch); err != nil {
+			return names, err
+		}
+		names = append(names, entry.Name)
+	}
+
+	return names, nil
+}
+
+func (m *Migrator) runUp(entry Entry, batch int) error {
+	return m.transaction(func(tx *gorm.DB) error {
+		if err := entry.Migration.Up(tx); err != nil {
+			return fmt.Errorf("migration %s failed: %w", entry.Name, err)
+		}
+
+		record := SchemaMigration{Migration: entry.Name, Batch: batch}
+		if err := tx.Create(&record).Error; err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", entry.Name, err)
+		}
+
+		return nil
+	})
+}
+
+// Rollback reverts migrations in reverse order. With steps <= 0 the whole
+// last batch is rolled back, otherwise the last steps migrations are.
+func (m *Migrator) Rollback(steps int) ([]string, error) {
+	records, err := m.rollbackCandidates(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.rollbackRecords(records)
+}
+
+// rollbackCandidates returns the records Rollback(steps) reverts, newest first
+func (m *Migrator) rollbackCandidates(steps int) ([]SchemaMigration, error) {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return nil, nil
+	}
+
+	query := m.db.Order("batch DESC").Order("id DESC")
+	if steps > 0 {
+		query = query.Limit(steps)
+	} else {
+		batch, err := m.lastBatch()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("batch = ?", batch)
+	}
+
+	var records []SchemaMigration
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	return records, nil
+}
+
+// Reset reverts every migration that has been run
+func (m *Migrator) Reset() ([]string, error) {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return nil, nil
+	}
+
+	var records []SchemaMigration
+	if err := m.db.Order("batch DESC").Order("id DESC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	return m.rollbackRecords(records)
+}
+
+// Status lists every known migration together with its batch number.
+// Migrations recorded in the database without a matching entry are included
+// so that missing files are easy to spot.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	ran, err := m.ran()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(m.entries))
+	statuses := make([]MigrationStatus, 0, len(m.entries))
+	for _, entry := range m.entries {
+		known[entry.Name] = true
+		record, ok := ran[entry.Name]
+		statuses = append(statuses, MigrationStatus{Name: entry.Name, Ran: ok, Batch: record.Batch})
+	}
+
+	for name, record := range ran {
+		if !known[name] {
+			statuses = append(statuses, MigrationStatus{Name: name, Ran: true, Batch: record.Batch})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses, nil
+}
+
+// DropAllTables drops every table in the database, including tables that
+// were not created by a migration. Where the dialect allows it the tables
+// are dropped in one transaction, so a failure leaves all of them in place.
+func (m *Migrator) DropAllTables() error {
+	tables, err := m.db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	sqlite := m.db.Dialector.Name() == "sqlite"
+
+	var drop []string
+	for _, table := range tables {
+		switch {
+		// Keep the lock table, the caller may be holding the migration lock
+		// on SQLite or a scheduled task its lock on any dialect
+		case table == lock.Table:
+		// SQLite's internal tables, such as sqlite_sequence, cannot be dropped
+		case sqlite && strings.HasPrefix(table, "sqlite_"):
+		default:
+			drop = append(drop, table)
+		}
+	}
+
+	return m.transaction(func(tx *gorm.DB) error {
+		for _, table := range drop {
+			if err := tx.Migrator().DropTable(table); err != nil {
+				return fmt.Errorf("failed to drop table %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) rollbackRecords(records []SchemaMigration) ([]string, error) {
+	var names []string
+	for _, record := range records {
+		entry, ok := m.find(record.Migration)
+		if !ok {
+			return names, fmt.Errorf("migration %s not found", record.Migration)
+		}
+
+		if err := m.runDown(entry, record); err != nil {
+			return names, err
+		}
+		names = append(names, entry.Name)
+	}
+
+	return names, nil
+}
+
+func (m *Migrator) runDown(entry Entry, record SchemaMigration) error {
+	return m.transaction(func(tx *gorm.DB) error {
+		if err := entry.Migration.Down(tx); err != nil {
+			return fmt.Errorf("rollback of %s failed: %w", entry.Name, err)
+		}
+
+		if err := tx.Delete(&record).Error; err != nil {
+			return fmt.Errorf("failed to remove migration record %s: %w", entry.Name, err)
+		}
+
+		return nil
+	})
+}
+
+func (m *Migrator) find(name string) (Entry, bool) {
+	for _, entry := range m.entries {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// transaction wraps fn in a database transaction when the dialect supports
+// transactional DDL. MySQL implicitly commits on every DDL statement, so a
+// transaction there would only give a false sense of safety.
+func (m *Migrator) transaction(fn func(tx *gorm.DB) error) error {
+	if m.db.Dialector.Name() == "mysql" {
+		return fn(m.db)
+	}
+
+	return m.db.Transaction(fn)
+}
+
+// prepare creates the schema_migrations table if it does not exist yet
+func (m *Migrator) prepare() error {
+	if m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return nil
+	}
+
+	if err := m.db.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) ran() (map[string]SchemaMigration, error) {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[string]SchemaMigration{}, nil
+	}
+
+	var records []SchemaMigration
+	if err := m.db.Order("id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	ran := make(map[string]SchemaMigration, len(records))
+	for _, record := range records {
+		ran[record.Migration] = record
+	}
+
+	return ran, nil
+}
+
+func (m *Migrator) lastBatch() (int, error) {
+	var batch int
+	if err := m.db.Model(&SchemaMigration{}).Select("COALESCE(MAX(batch), 0)").Scan(&batch).Error; err != nil {
+		return 0, fmt.Errorf("failed to read last batch: %w", err)
+	}
+
+	return batch, nil
+}
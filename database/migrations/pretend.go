@@ -0,0 +1,159 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PretendedMigration holds the SQL a migration would execute
+type PretendedMigration struct {
+	Name       string
+	Statements []string
+}
+
+// pretendPool is a dry-run connection pool. GORM's DryRun session skips
+// every query, which makes AutoMigrate panic as soon as it inspects an
+// existing table. pretendPool instead answers read queries from the real
+// database and records write statements without executing them.
+type pretendPool struct {
+	pool       gorm.ConnPool
+	dialector  gorm.Dialector
+	statements []string
+}
+
+func (p *pretendPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported in pretend mode")
+}
+
+func (p *pretendPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	// Savepoints come from nested transactions, which are no-ops here
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SAVEPOINT") {
+		p.record(query, args)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (p *pretendPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if !isReadQuery(query) {
+		// e.g. INSERT ... RETURNING on postgres and sqlite: record it and
+		// hand GORM an empty result, as ExecContext does
+		p.record(query, args)
+		return p.pool.QueryContext(ctx, p.emptyQuery())
+	}
+	return p.pool.QueryContext(ctx, query, args...)
+}
+
+func (p *pretendPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if !isReadQuery(query) {
+		// Record the write and hand GORM an empty result instead
+		p.record(query, args)
+		return p.pool.QueryRowContext(ctx, p.emptyQuery())
+	}
+	return p.pool.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx lets migrations open transactions; the pool itself stands in
+// for the transaction since nothing is written anyway
+func (p *pretendPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+
+func (p *pretendPool) Commit() error {
+	return nil
+}
+
+func (p *pretendPool) Rollback() error {
+	return nil
+}
+
+// emptyQuery selects no rows. MySQL 5.7 needs FROM DUAL for a WHERE.
+func (p *pretendPool) emptyQuery() string {
+	if p.dialector.Name() == "mysql" {
+		return "SELECT 1 FROM DUAL WHERE 1 = 0"
+	}
+	return "SELECT 1 WHERE 1 = 0"
+}
+
+func (p *pretendPool) record(query string, args []interface{}) {
+	p.statements = append(p.statements, p.dialector.Explain(query, args...))
+}
+
+func isReadQuery(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "SHOW", "PRAGMA", "DESCRIBE", "DESC", "EXPLAIN", "WITH":
+		return true
+	}
+	return false
+}
+
+// pretend runs fn against a session that records SQL instead of executing it
+func (m *Migrator) pretend(fn func(tx *gorm.DB) error) ([]string, error) {
+	pool := &pretendPool{pool: m.db.ConnPool, dialector: m.db.Dialector}
+
+	tx := m.db.Session(&gorm.Session{NewDB: true, SkipDefaultTransaction: true})
+	tx.Statement.ConnPool = pool
+
+	err := fn(tx)
+	return pool.statements, err
+}
+
+// PretendAutoMigrate returns the SQL AutoMigrate would run for models
+func (m *Migrator) PretendAutoMigrate(models ...interface{}) ([]string, error) {
+	return m.pretend(func(tx *gorm.DB) error {
+		return tx.AutoMigrate(models...)
+	})
+}
+
+// PretendRun returns the SQL of every pending migration's Up method
+func (m *Migrator) PretendRun() ([]PretendedMigration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var pretended []PretendedMigration
+	for _, entry := range pending {
+		statements, err := m.pretend(entry.Migration.Up)
+		pretended = append(pretended, PretendedMigration{Name: entry.Name, Statements: statements})
+		if err != nil {
+			return pretended, fmt.Errorf("migration %s failed: %w", entry.Name, err)
+		}
+	}
+
+	return pretended, nil
+}
+
+// PretendRollback returns the SQL of the Down methods Rollback(steps) would run
+func (m *Migrator) PretendRollback(steps int) ([]PretendedMigration, error) {
+	records, err := m.rollbackCandidates(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	var pretended []PretendedMigration
+	for _, record := range records {
+		entry, ok := m.find(record.Migration)
+		if !ok {
+			return pretended, fmt.Errorf("migration %s not found", record.Migration)
+		}
+
+		statements, err := m.pretend(entry.Migration.Down)
+		pretended = append(pretended, PretendedMigration{Name: entry.Name, Statements: statements})
+		if err != nil {
+			return pretended, fmt.Errorf("rollback of %s failed: %w", entry.Name, err)
+		}
+	}
+
+	return pretended, nil
+}
@@ -0,0 +1,14 @@
+package migrations
+
+// migrations holds every registered versioned migration
+var migrations []Entry
+
+// Register adds a versioned migration to the migrator. Migration files call
+// it from init() with their own file name, e.g.
+//
+//	func init() {
+//		Register("20251202190735_create_products_table", &CreateProductsTable{})
+//	}
+func Register(name string, migration Migration) {
+	migrations = append(migrations, Entry{Name: name, Migration: migration})
+}
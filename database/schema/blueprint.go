@@ -0,0 +1,405 @@
+package schema
+
+import (
+	"strings"
+)
+
+// Blueprint collects the columns, indexes and constraints of a table
+// definition. It is passed to the callbacks of Create and Table.
+type Blueprint struct {
+	table    string
+	creating bool
+	columns  []*Column
+	commands []command
+}
+
+func newBlueprint(table string, creating bool) *Blueprint {
+	return &Blueprint{table: table, creating: creating}
+}
+
+// Column types understood by the grammars
+const (
+	typeBigIncrements = "bigIncrements"
+	typeIncrements    = "increments"
+	typeString        = "string"
+	typeChar          = "char"
+	typeText          = "text"
+	typeLongText      = "longText"
+	typeInteger       = "integer"
+	typeSmallInteger  = "smallInteger"
+	typeBigInteger    = "bigInteger"
+	typeBoolean       = "boolean"
+	typeDecimal       = "decimal"
+	typeFloat         = "float"
+	typeDouble        = "double"
+	typeDate          = "date"
+	typeDateTime      = "dateTime"
+	typeTime          = "time"
+	typeJSON          = "json"
+	typeUUID          = "uuid"
+	typeRaw           = "raw"
+)
+
+// Column is a column definition. Columns are NOT NULL unless Nullable is called.
+type Column struct {
+	name       string
+	kind       string
+	rawType    string
+	length     int
+	precision  int
+	scale      int
+	nullable   bool
+	unsigned   bool
+	hasDefault bool
+	def        interface{}
+	unique     bool
+	index      bool
+}
+
+// Nullable allows NULL values in the column
+func (c *Column) Nullable() *Column {
+	c.nullable = true
+	return c
+}
+
+// Default sets the column default. Use Expression for raw SQL defaults.
+func (c *Column) Default(value interface{}) *Column {
+	c.hasDefault = true
+	c.def = value
+	return c
+}
+
+// Unsigned marks a numeric column as unsigned (MySQL only)
+func (c *Column) Unsigned() *Column {
+	c.unsigned = true
+	return c
+}
+
+// Unique adds a unique index on the column
+func (c *Column) Unique() *Column {
+	c.unique = true
+	return c
+}
+
+// Index adds an index on the column
+func (c *Column) Index() *Column {
+	c.index = true
+	return c
+}
+
+// Expression is a raw SQL expression used as a column default,
+// e.g. schema.Expression("CURRENT_TIMESTAMP")
+type Expression string
+
+func (t *Blueprint) addColumn(kind, name string) *Column {
+	c := &Column{name: name, kind: kind}
+	t.columns = append(t.columns, c)
+	return c
+}
+
+// ID adds an auto-incrementing big integer primary key named "id"
+func (t *Blueprint) ID() *Column {
+	return t.BigIncrements("id")
+}
+
+// BigIncrements adds an auto-incrementing big integer primary key
+func (t *Blueprint) BigIncrements(name string) *Column {
+	return t.addColumn(typeBigIncrements, name)
+}
+
+// Increments adds an auto-incrementing integer primary key
+func (t *Blueprint) Increments(name string) *Column {
+	return t.addColumn(typeIncrements, name)
+}
+
+// String adds a VARCHAR column
+func (t *Blueprint) String(name string, length int) *Column {
+	c := t.addColumn(typeString, name)
+	c.length = length
+	return c
+}
+
+// Char adds a fixed-length CHAR column
+func (t *Blueprint) Char(name string, length int) *Column {
+	c := t.addColumn(typeChar, name)
+	c.length = length
+	return c
+}
+
+// Text adds a TEXT column
+func (t *Blueprint) Text(name string) *Column {
+	return t.addColumn(typeText, name)
+}
+
+// LongText adds a LONGTEXT column (TEXT outside MySQL)
+func (t *Blueprint) LongText(name string) *Column {
+	return t.addColumn(typeLongText, name)
+}
+
+// Integer adds an INTEGER column
+func (t *Blueprint) Integer(name string) *Column {
+	return t.addColumn(typeInteger, name)
+}
+
+// SmallInteger adds a SMALLINT column
+func (t *Blueprint) SmallInteger(name string) *Column {
+	return t.addColumn(typeSmallInteger, name)
+}
+
+// BigInteger adds a BIGINT column
+func (t *Blueprint) BigInteger(name string) *Column {
+	return t.addColumn(typeBigInteger, name)
+}
+
+// UnsignedBigInteger adds an unsigned BIGINT column
+func (t *Blueprint) UnsignedBigInteger(name string) *Column {
+	return t.BigInteger(name).Unsigned()
+}
+
+// ForeignID adds an unsigned BIGINT column meant to reference another table's id
+func (t *Blueprint) ForeignID(name string) *Column {
+	return t.UnsignedBigInteger(name)
+}
+
+// Boolean adds a BOOLEAN column
+func (t *Blueprint) Boolean(name string) *Column {
+	return t.addColumn(typeBoolean, name)
+}
+
+// Decimal adds a DECIMAL column with the given precision and scale
+func (t *Blueprint) Decimal(name string, precision, scale int) *Column {
+	c := t.addColumn(typeDecimal, name)
+	c.precision = precision
+	c.scale = scale
+	return c
+}
+
+// Float adds a single precision floating point column
+func (t *Blueprint) Float(name string) *Column {
+	return t.addColumn(typeFloat, name)
+}
+
+// Double adds a double precision floating point column
+func (t *Blueprint) Double(name string) *Column {
+	return t.addColumn(typeDouble, name)
+}
+
+// Date adds a DATE column
+func (t *Blueprint) Date(name string) *Column {
+	return t.addColumn(typeDate, name)
+}
+
+// DateTime adds a date and time column
+func (t *Blueprint) DateTime(name string) *Column {
+	return t.addColumn(typeDateTime, name)
+}
+
+// Timestamp adds a date and time column (alias of DateTime)
+func (t *Blueprint) Timestamp(name string) *Column {
+	return t.DateTime(name)
+}
+
+// Time adds a TIME column
+func (t *Blueprint) Time(name string) *Column {
+	return t.addColumn(typeTime, name)
+}
+
+// JSON adds a JSON column (JSONB on postgres, TEXT on sqlite)
+func (t *Blueprint) JSON(name string) *Column {
+	return t.addColumn(typeJSON, name)
+}
+
+// UUID adds a UUID column
+func (t *Blueprint) UUID(name string) *Column {
+	return t.addColumn(typeUUID, name)
+}
+
+// Column adds a column with a raw, dialect specific SQL type
+func (t *Blueprint) Column(name, sqlType string) *Column {
+	c := t.addColumn(typeRaw, name)
+	c.rawType = sqlType
+	return c
+}
+
+// Timestamps adds the nullable created_at and updated_at columns used by models.BaseModel
+func (t *Blueprint) Timestamps() {
+	t.DateTime("created_at").Nullable()
+	t.DateTime("updated_at").Nullable()
+}
+
+// SoftDeletes adds the nullable, indexed deleted_at column used by gorm.DeletedAt
+func (t *Blueprint) SoftDeletes() {
+	t.DateTime("deleted_at").Nullable().Index()
+}
+
+// command is a table level operation compiled after the columns
+type command interface {
+	compile(g grammar, t *Blueprint) ([]string, error)
+}
+
+// IndexDefinition is an index added with Index, Unique or Primary
+type IndexDefinition struct {
+	kind    string
+	name    string
+	columns []string
+}
+
+// Name overrides the generated index name
+func (i *IndexDefinition) Name(name string) *IndexDefinition {
+	i.name = name
+	return i
+}
+
+func (i *IndexDefinition) compile(g grammar, t *Blueprint) ([]string, error) {
+	if i.kind == "primary" {
+		return []string{g.compilePrimary(t.table, i.columns)}, nil
+	}
+	return []string{g.compileIndex(t.table, i.name, i.columns, i.kind == "unique")}, nil
+}
+
+func (t *Blueprint) addIndex(kind string, columns []string) *IndexDefinition {
+	i := &IndexDefinition{kind: kind, name: indexName(t.table, columns), columns: columns}
+	t.commands = append(t.commands, i)
+	return i
+}
+
+// Index adds an index over the given columns
+func (t *Blueprint) Index(columns ...string) *IndexDefinition {
+	return t.addIndex("index", columns)
+}
+
+// Unique adds a unique index over the given columns
+func (t *Blueprint) Unique(columns ...string) *IndexDefinition {
+	return t.addIndex("unique", columns)
+}
+
+// Primary adds a composite primary key
+func (t *Blueprint) Primary(columns ...string) *IndexDefinition {
+	return t.addIndex("primary", columns)
+}
+
+// ForeignKey is a foreign key constraint added with Foreign
+type ForeignKey struct {
+	name       string
+	columns    []string
+	references []string
+	on         string
+	onDelete   string
+	onUpdate   string
+}
+
+// References sets the referenced columns (defaults to "id")
+func (f *ForeignKey) References(columns ...string) *ForeignKey {
+	f.references = columns
+	return f
+}
+
+// On sets the referenced table
+func (f *ForeignKey) On(table string) *ForeignKey {
+	f.on = table
+	return f
+}
+
+// OnDelete sets the ON DELETE action, e.g. "cascade" or "set null"
+func (f *ForeignKey) OnDelete(action string) *ForeignKey {
+	f.onDelete = strings.ToUpper(action)
+	return f
+}
+
+// OnUpdate sets the ON UPDATE action
+func (f *ForeignKey) OnUpdate(action string) *ForeignKey {
+	f.onUpdate = strings.ToUpper(action)
+	return f
+}
+
+// Name overrides the generated constraint name
+func (f *ForeignKey) Name(name string) *ForeignKey {
+	f.name = name
+	return f
+}
+
+func (f *ForeignKey) compile(g grammar, t *Blueprint) ([]string, error) {
+	return g.compileAddForeign(t.table, f)
+}
+
+// Foreign adds a foreign key constraint, e.g.
+//
+//	t.Foreign("category_id").References("id").On("categories").OnDelete("cascade")
+func (t *Blueprint) Foreign(columns ...string) *ForeignKey {
+	f := &ForeignKey{
+		name:       "fk_" + t.table + "_" + strings.Join(columns, "_"),
+		columns:    columns,
+		references: []string{"id"},
+	}
+	t.commands = append(t.commands, f)
+	return f
+}
+
+type dropColumns struct{ columns []string }
+
+func (d dropColumns) compile(g grammar, t *Blueprint) ([]string, error) {
+	var statements []string
+	for _, column := range d.columns {
+		statements = append(statements, g.compileDropColumn(t.table, column))
+	}
+	return statements, nil
+}
+
+type renameColumn struct{ from, to string }
+
+func (r renameColumn) compile(g grammar, t *Blueprint) ([]string, error) {
+	return []string{g.compileRenameColumn(t.table, r.from, r.to)}, nil
+}
+
+type dropIndex struct{ name string }
+
+func (d dropIndex) compile(g grammar, t *Blueprint) ([]string, error) {
+	return []string{g.compileDropIndex(t.table, d.name)}, nil
+}
+
+type dropForeign struct{ name string }
+
+func (d dropForeign) compile(g grammar, t *Blueprint) ([]string, error) {
+	return g.compileDropForeign(t.table, d.name)
+}
+
+// DropColumn drops one or more columns
+func (t *Blueprint) DropColumn(columns ...string) {
+	t.commands = append(t.commands, dropColumns{columns: columns})
+}
+
+// RenameColumn renames a column
+func (t *Blueprint) RenameColumn(from, to string) {
+	t.commands = append(t.commands, renameColumn{from: from, to: to})
+}
+
+// DropIndex drops an index by name
+func (t *Blueprint) DropIndex(name string) {
+	t.commands = append(t.commands, dropIndex{name: name})
+}
+
+// DropUnique drops a unique index by name
+func (t *Blueprint) DropUnique(name string) {
+	t.DropIndex(name)
+}
+
+// DropForeign drops a foreign key constraint by name
+func (t *Blueprint) DropForeign(name string) {
+	t.commands = append(t.commands, dropForeign{name: name})
+}
+
+// DropTimestamps drops the created_at and updated_at columns
+func (t *Blueprint) DropTimestamps() {
+	t.DropColumn("created_at", "updated_at")
+}
+
+// DropSoftDeletes drops the deleted_at column and its index
+func (t *Blueprint) DropSoftDeletes() {
+	t.DropIndex(indexName(t.table, []string{"deleted_at"}))
+	t.DropColumn("deleted_at")
+}
+
+// indexName follows GORM's naming so indexes created here and by AutoMigrate match
+func indexName(table string, columns []string) string {
+	return "idx_" + table + "_" + strings.Join(columns, "_")
+}
@@ -0,0 +1,177 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// grammar compiles blueprints into the SQL of a single dialect
+type grammar interface {
+	quote(name string) string
+	columnType(c *Column) string
+	incrementsDefinition(c *Column) string
+	boolLiteral(v bool) string
+	compileRename(from, to string) string
+	compileDropIndex(table, name string) string
+	compileAddForeign(table string, f *ForeignKey) ([]string, error)
+	compileDropForeign(table, name string) ([]string, error)
+	compilePrimary(table string, columns []string) string
+	compileIndex(table, name string, columns []string, unique bool) string
+	compileDropColumn(table, column string) string
+	compileRenameColumn(table, from, to string) string
+}
+
+func (t *Blueprint) compile(g grammar) ([]string, error) {
+	var statements []string
+	commands := t.commands
+
+	if t.creating {
+		var definitions []string
+		for _, c := range t.columns {
+			definitions = append(definitions, columnDefinition(g, c))
+		}
+
+		// Primary keys and foreign keys are declared inline so that sqlite,
+		// which cannot add them to an existing table, is supported too
+		commands = nil
+		for _, cmd := range t.commands {
+			switch cmd := cmd.(type) {
+			case *IndexDefinition:
+				if cmd.kind != "primary" {
+					commands = append(commands, cmd)
+					continue
+				}
+				definitions = append(definitions, "PRIMARY KEY ("+quoteList(g, cmd.columns)+")")
+			case *ForeignKey:
+				definitions = append(definitions, foreignDefinition(g, cmd))
+			default:
+				commands = append(commands, cmd)
+			}
+		}
+
+		statements = append(statements, fmt.Sprintf("CREATE TABLE %s (\n  %s\n)",
+			g.quote(t.table), strings.Join(definitions, ",\n  ")))
+	} else {
+		for _, c := range t.columns {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s",
+				g.quote(t.table), columnDefinition(g, c)))
+		}
+	}
+
+	for _, c := range t.columns {
+		if c.unique {
+			statements = append(statements, g.compileIndex(t.table, indexName(t.table, []string{c.name}), []string{c.name}, true))
+		}
+		if c.index {
+			statements = append(statements, g.compileIndex(t.table, indexName(t.table, []string{c.name}), []string{c.name}, false))
+		}
+	}
+
+	for _, cmd := range commands {
+		compiled, err := cmd.compile(g, t)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, compiled...)
+	}
+
+	return statements, nil
+}
+
+func columnDefinition(g grammar, c *Column) string {
+	if c.kind == typeBigIncrements || c.kind == typeIncrements {
+		return g.quote(c.name) + " " + g.incrementsDefinition(c)
+	}
+
+	sql := g.quote(c.name) + " " + g.columnType(c)
+
+	if c.nullable {
+		sql += " NULL"
+	} else {
+		sql += " NOT NULL"
+	}
+
+	if c.hasDefault {
+		sql += " DEFAULT " + defaultValue(g, c.def)
+	}
+
+	return sql
+}
+
+func defaultValue(g grammar, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case Expression:
+		return string(v)
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		return g.boolLiteral(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func foreignDefinition(g grammar, f *ForeignKey) string {
+	sql := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		g.quote(f.name), quoteList(g, f.columns), g.quote(f.on), quoteList(g, f.references))
+
+	if f.onDelete != "" {
+		sql += " ON DELETE " + f.onDelete
+	}
+	if f.onUpdate != "" {
+		sql += " ON UPDATE " + f.onUpdate
+	}
+
+	return sql
+}
+
+func quoteList(g grammar, names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = g.quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// ansiGrammar holds the statements shared by postgres and sqlite
+type ansiGrammar struct{}
+
+func (ansiGrammar) quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (g ansiGrammar) compileRename(from, to string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", g.quote(from), g.quote(to))
+}
+
+func (g ansiGrammar) compileDropIndex(table, name string) string {
+	return "DROP INDEX " + g.quote(name)
+}
+
+func (g ansiGrammar) compileIndex(table, name string, columns []string, unique bool) string {
+	return createIndex(g.quote, table, name, columns, unique)
+}
+
+func (g ansiGrammar) compileDropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", g.quote(table), g.quote(column))
+}
+
+func (g ansiGrammar) compileRenameColumn(table, from, to string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", g.quote(table), g.quote(from), g.quote(to))
+}
+
+func createIndex(quote func(string) string, table, name string, columns []string, unique bool) string {
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quote(column)
+	}
+
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, quote(name), quote(table), strings.Join(quoted, ", "))
+}
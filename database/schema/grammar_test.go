@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToSQL(t *testing.T) {
+	products := func(t *Blueprint) {
+		t.ID()
+		t.String("name", 255).Unique()
+		t.Decimal("price", 12, 2).Default(0)
+		t.Boolean("active").Default(true)
+		t.Text("notes").Nullable()
+	}
+
+	tests := []struct {
+		name     string
+		dialect  string
+		table    string
+		creating bool
+		fn       func(t *Blueprint)
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "create mysql", dialect: "mysql", table: "products", creating: true, fn: products,
+			want: []string{
+				"CREATE TABLE `products` (\n" +
+					"  `id` bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,\n" +
+					"  `name` varchar(255) NOT NULL,\n" +
+					"  `price` decimal(12,2) NOT NULL DEFAULT 0,\n" +
+					"  `active` boolean NOT NULL DEFAULT 1,\n" +
+					"  `notes` text NULL\n)",
+				"CREATE UNIQUE INDEX `idx_products_name` ON `products` (`name`)",
+			},
+		},
+		{
+			name: "create postgres", dialect: "postgres", table: "products", creating: true, fn: products,
+			want: []string{
+				`CREATE TABLE "products" (` + "\n" +
+					`  "id" bigserial PRIMARY KEY,` + "\n" +
+					`  "name" varchar(255) NOT NULL,` + "\n" +
+					`  "price" decimal(12,2) NOT NULL DEFAULT 0,` + "\n" +
+					`  "active" boolean NOT NULL DEFAULT true,` + "\n" +
+					`  "notes" text NULL` + "\n)",
+				`CREATE UNIQUE INDEX "idx_products_name" ON "products" ("name")`,
+			},
+		},
+		{
+			name: "create sqlite", dialect: "sqlite", table: "products", creating: true, fn: products,
+			want: []string{
+				`CREATE TABLE "products" (` + "\n" +
+					`  "id" integer PRIMARY KEY AUTOINCREMENT,` + "\n" +
+					`  "name" text NOT NULL,` + "\n" +
+					`  "price" real NOT NULL DEFAULT 0,` + "\n" +
+					`  "active" numeric NOT NULL DEFAULT true,` + "\n" +
+					`  "notes" text NULL` + "\n)",
+				`CREATE UNIQUE INDEX "idx_products_name" ON "products" ("name")`,
+			},
+		},
+		{
+			name: "quoting mysql", dialect: "mysql", table: "odd`table", creating: false,
+			fn:   func(t *Blueprint) { t.Integer("we`ird") },
+			want: []string{"ALTER TABLE `odd``table` ADD COLUMN `we``ird` int NOT NULL"},
+		},
+		{
+			name: "quoting postgres", dialect: "postgres", table: `odd"table`, creating: false,
+			fn:   func(t *Blueprint) { t.Integer(`we"ird`) },
+			want: []string{`ALTER TABLE "odd""table" ADD COLUMN "we""ird" integer NOT NULL`},
+		},
+		{
+			name: "string defaults", dialect: "postgres", table: "products", creating: false,
+			fn: func(t *Blueprint) {
+				t.String("status", 20).Default("it's")
+				t.DateTime("seen_at").Nullable().Default(nil)
+				t.DateTime("created_at").Default(Expression("CURRENT_TIMESTAMP"))
+			},
+			want: []string{
+				`ALTER TABLE "products" ADD COLUMN "status" varchar(20) NOT NULL DEFAULT 'it''s'`,
+				`ALTER TABLE "products" ADD COLUMN "seen_at" timestamptz NULL DEFAULT NULL`,
+				`ALTER TABLE "products" ADD COLUMN "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+			},
+		},
+		{
+			name: "boolean false mysql", dialect: "mysql", table: "products", creating: false,
+			fn:   func(t *Blueprint) { t.Boolean("active").Default(false) },
+			want: []string{"ALTER TABLE `products` ADD COLUMN `active` boolean NOT NULL DEFAULT 0"},
+		},
+		{
+			name: "foreign id postgres", dialect: "postgres", table: "products", creating: false,
+			fn: func(t *Blueprint) { t.ForeignID("user_id").Index() },
+			want: []string{
+				`ALTER TABLE "products" ADD COLUMN "user_id" bigint NOT NULL`,
+				`CREATE INDEX "idx_products_user_id" ON "products" ("user_id")`,
+			},
+		},
+		{
+			name: "inline keys", dialect: "sqlite", table: "order_items", creating: true,
+			fn: func(t *Blueprint) {
+				t.ForeignID("order_id")
+				t.ForeignID("product_id")
+				t.Primary("order_id", "product_id")
+				t.Foreign("order_id").On("orders").OnDelete("cascade")
+				t.Index("product_id").Name("by_product")
+			},
+			want: []string{
+				`CREATE TABLE "order_items" (` + "\n" +
+					`  "order_id" integer NOT NULL,` + "\n" +
+					`  "product_id" integer NOT NULL,` + "\n" +
+					`  PRIMARY KEY ("order_id", "product_id"),` + "\n" +
+					`  CONSTRAINT "fk_order_items_order_id" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE` + "\n)",
+				`CREATE INDEX "by_product" ON "order_items" ("product_id")`,
+			},
+		},
+		{
+			name: "alter mysql", dialect: "mysql", table: "products", creating: false,
+			fn: func(t *Blueprint) {
+				t.Foreign("user_id").On("users").OnUpdate("set null")
+				t.DropForeign("fk_old")
+				t.RenameColumn("a", "b")
+				t.DropSoftDeletes()
+			},
+			want: []string{
+				"ALTER TABLE `products` ADD CONSTRAINT `fk_products_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE SET NULL",
+				"ALTER TABLE `products` DROP FOREIGN KEY `fk_old`",
+				"ALTER TABLE `products` RENAME COLUMN `a` TO `b`",
+				"DROP INDEX `idx_products_deleted_at` ON `products`",
+				"ALTER TABLE `products` DROP COLUMN `deleted_at`",
+			},
+		},
+		{
+			name: "alter postgres", dialect: "postgres", table: "products", creating: false,
+			fn: func(t *Blueprint) {
+				t.DropForeign("fk_old")
+				t.DropIndex("idx_old")
+				t.Primary("id")
+			},
+			want: []string{
+				`ALTER TABLE "products" DROP CONSTRAINT "fk_old"`,
+				`DROP INDEX "idx_old"`,
+				`ALTER TABLE "products" ADD PRIMARY KEY ("id")`,
+			},
+		},
+		{
+			name: "primary sqlite", dialect: "sqlite", table: "products", creating: false,
+			fn:   func(t *Blueprint) { t.Primary("id") },
+			want: []string{`CREATE UNIQUE INDEX "pk_products" ON "products" ("id")`},
+		},
+		{
+			name: "add foreign sqlite", dialect: "sqlite", table: "products", creating: false,
+			fn:      func(t *Blueprint) { t.Foreign("user_id").On("users") },
+			wantErr: true,
+		},
+		{
+			name: "drop foreign sqlite", dialect: "sqlite", table: "products", creating: false,
+			fn:      func(t *Blueprint) { t.DropForeign("fk_old") },
+			wantErr: true,
+		},
+		{
+			name: "unknown dialect", dialect: "sqlserver", table: "products", creating: true,
+			fn:      func(t *Blueprint) { t.ID() },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToSQL(tt.dialect, tt.table, tt.creating, tt.fn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToSQL error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexName(t *testing.T) {
+	tests := []struct {
+		table   string
+		columns []string
+		want    string
+	}{
+		{"users", []string{"email"}, "idx_users_email"},
+		{"order_items", []string{"order_id", "product_id"}, "idx_order_items_order_id_product_id"},
+		{"products", []string{"deleted_at"}, "idx_products_deleted_at"},
+	}
+
+	for _, tt := range tests {
+		if got := indexName(tt.table, tt.columns); got != tt.want {
+			t.Errorf("indexName(%q, %q) = %q, want %q", tt.table, tt.columns, got, tt.want)
+		}
+	}
+}
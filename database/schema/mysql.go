@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type mysqlGrammar struct{}
+
+func (mysqlGrammar) quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (mysqlGrammar) columnType(c *Column) string {
+	var sqlType string
+
+	switch c.kind {
+	case typeString:
+		sqlType = fmt.Sprintf("varchar(%d)", c.length)
+	case typeChar:
+		sqlType = fmt.Sprintf("char(%d)", c.length)
+	case typeText:
+		sqlType = "text"
+	case typeLongText:
+		sqlType = "longtext"
+	case typeInteger:
+		sqlType = "int"
+	case typeSmallInteger:
+		sqlType = "smallint"
+	case typeBigInteger:
+		sqlType = "bigint"
+	case typeBoolean:
+		sqlType = "boolean"
+	case typeDecimal:
+		sqlType = fmt.Sprintf("decimal(%d,%d)", c.precision, c.scale)
+	case typeFloat:
+		sqlType = "float"
+	case typeDouble:
+		sqlType = "double"
+	case typeDate:
+		sqlType = "date"
+	case typeDateTime:
+		sqlType = "datetime(3)"
+	case typeTime:
+		sqlType = "time"
+	case typeJSON:
+		sqlType = "json"
+	case typeUUID:
+		sqlType = "char(36)"
+	default:
+		sqlType = c.rawType
+	}
+
+	if c.unsigned {
+		sqlType += " unsigned"
+	}
+
+	return sqlType
+}
+
+func (mysqlGrammar) incrementsDefinition(c *Column) string {
+	if c.kind == typeIncrements {
+		return "int unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY"
+	}
+	return "bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY"
+}
+
+func (mysqlGrammar) boolLiteral(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+func (g mysqlGrammar) compileRename(from, to string) string {
+	return fmt.Sprintf("RENAME TABLE %s TO %s", g.quote(from), g.quote(to))
+}
+
+func (g mysqlGrammar) compileDropIndex(table, name string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s", g.quote(name), g.quote(table))
+}
+
+func (g mysqlGrammar) compileAddForeign(table string, f *ForeignKey) ([]string, error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", g.quote(table), foreignDefinition(g, f))}, nil
+}
+
+func (g mysqlGrammar) compileDropForeign(table, name string) ([]string, error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", g.quote(table), g.quote(name))}, nil
+}
+
+func (g mysqlGrammar) compilePrimary(table string, columns []string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", g.quote(table), quoteList(g, columns))
+}
+
+func (g mysqlGrammar) compileIndex(table, name string, columns []string, unique bool) string {
+	return createIndex(g.quote, table, name, columns, unique)
+}
+
+func (g mysqlGrammar) compileDropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", g.quote(table), g.quote(column))
+}
+
+func (g mysqlGrammar) compileRenameColumn(table, from, to string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", g.quote(table), g.quote(from), g.quote(to))
+}
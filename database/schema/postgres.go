@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"fmt"
+)
+
+type postgresGrammar struct {
+	ansiGrammar
+}
+
+func (postgresGrammar) columnType(c *Column) string {
+	switch c.kind {
+	case typeString:
+		return fmt.Sprintf("varchar(%d)", c.length)
+	case typeChar:
+		return fmt.Sprintf("char(%d)", c.length)
+	case typeText, typeLongText:
+		return "text"
+	case typeInteger:
+		return "integer"
+	case typeSmallInteger:
+		return "smallint"
+	case typeBigInteger:
+		return "bigint"
+	case typeBoolean:
+		return "boolean"
+	case typeDecimal:
+		return fmt.Sprintf("decimal(%d,%d)", c.precision, c.scale)
+	case typeFloat:
+		return "real"
+	case typeDouble:
+		return "double precision"
+	case typeDate:
+		return "date"
+	case typeDateTime:
+		return "timestamptz"
+	case typeTime:
+		return "time"
+	case typeJSON:
+		return "jsonb"
+	case typeUUID:
+		return "uuid"
+	default:
+		return c.rawType
+	}
+}
+
+func (postgresGrammar) incrementsDefinition(c *Column) string {
+	if c.kind == typeIncrements {
+		return "serial PRIMARY KEY"
+	}
+	return "bigserial PRIMARY KEY"
+}
+
+func (postgresGrammar) boolLiteral(v bool) string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
+
+func (g postgresGrammar) compileAddForeign(table string, f *ForeignKey) ([]string, error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", g.quote(table), foreignDefinition(g, f))}, nil
+}
+
+func (g postgresGrammar) compileDropForeign(table, name string) ([]string, error) {
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", g.quote(table), g.quote(name))}, nil
+}
+
+func (g postgresGrammar) compilePrimary(table string, columns []string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", g.quote(table), quoteList(g, columns))
+}
@@ -0,0 +1,125 @@
+// Package schema provides a dialect-aware table builder for migrations.
+//
+//	func (m *CreateProductsTable) Up(db *gorm.DB) error {
+//		return schema.Create(db, "products", func(t *schema.Blueprint) {
+//			t.ID()
+//			t.String("name", 255)
+//			t.Decimal("price", 12, 2).Default(0)
+//			t.Timestamps()
+//			t.SoftDeletes()
+//		})
+//	}
+//
+// The same migration produces valid DDL for the mysql, postgres and sqlite
+// drivers supported by config.ConnectDatabase.
+package schema
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Create creates a new table
+func Create(db *gorm.DB, table string, fn func(t *Blueprint)) error {
+	t := newBlueprint(table, true)
+	fn(t)
+	return run(db, t)
+}
+
+// Table alters an existing table
+func Table(db *gorm.DB, table string, fn func(t *Blueprint)) error {
+	t := newBlueprint(table, false)
+	fn(t)
+	return run(db, t)
+}
+
+// Drop drops a table
+func Drop(db *gorm.DB, table string) error {
+	g, err := grammarFor(db)
+	if err != nil {
+		return err
+	}
+	return exec(db, []string{"DROP TABLE " + g.quote(table)})
+}
+
+// DropIfExists drops a table if it exists
+func DropIfExists(db *gorm.DB, table string) error {
+	g, err := grammarFor(db)
+	if err != nil {
+		return err
+	}
+	return exec(db, []string{"DROP TABLE IF EXISTS " + g.quote(table)})
+}
+
+// Rename renames a table
+func Rename(db *gorm.DB, from, to string) error {
+	g, err := grammarFor(db)
+	if err != nil {
+		return err
+	}
+	return exec(db, []string{g.compileRename(from, to)})
+}
+
+// HasTable reports whether a table exists
+func HasTable(db *gorm.DB, table string) bool {
+	return db.Migrator().HasTable(table)
+}
+
+// HasColumn reports whether a table has the given column
+func HasColumn(db *gorm.DB, table, column string) bool {
+	return db.Migrator().HasColumn(table, column)
+}
+
+// ToSQL returns the statements a blueprint compiles to for a dialect
+// ("mysql", "postgres" or "sqlite") without executing them
+func ToSQL(dialect, table string, creating bool, fn func(t *Blueprint)) ([]string, error) {
+	g, err := grammarByName(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newBlueprint(table, creating)
+	fn(t)
+	return t.compile(g)
+}
+
+func run(db *gorm.DB, t *Blueprint) error {
+	g, err := grammarFor(db)
+	if err != nil {
+		return err
+	}
+
+	statements, err := t.compile(g)
+	if err != nil {
+		return err
+	}
+
+	return exec(db, statements)
+}
+
+func exec(db *gorm.DB, statements []string) error {
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("schema: %w", err)
+		}
+	}
+	return nil
+}
+
+func grammarFor(db *gorm.DB) (grammar, error) {
+	return grammarByName(db.Dialector.Name())
+}
+
+func grammarByName(dialect string) (grammar, error) {
+	switch dialect {
+	case "mysql":
+		return mysqlGrammar{}, nil
+	case "postgres":
+		return postgresGrammar{}, nil
+	case "sqlite":
+		return sqliteGrammar{}, nil
+	default:
+		return nil, fmt.Errorf("schema: unsupported dialect %q", dialect)
+	}
+}
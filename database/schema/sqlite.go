@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"fmt"
+)
+
+type sqliteGrammar struct {
+	ansiGrammar
+}
+
+func (sqliteGrammar) columnType(c *Column) string {
+	switch c.kind {
+	// SQLite ignores lengths; text is also what GORM uses for strings, so
+	// AutoMigrate leaves these columns alone
+	case typeString, typeChar, typeText, typeLongText, typeJSON, typeUUID:
+		return "text"
+	case typeInteger, typeSmallInteger, typeBigInteger:
+		return "integer"
+	case typeBoolean:
+		return "numeric"
+	// SQLite has no fixed point type and GORM uses real for decimals as well
+	case typeDecimal, typeFloat, typeDouble:
+		return "real"
+	case typeDate:
+		return "date"
+	case typeDateTime:
+		return "datetime"
+	case typeTime:
+		return "time"
+	default:
+		return c.rawType
+	}
+}
+
+func (sqliteGrammar) incrementsDefinition(c *Column) string {
+	return "integer PRIMARY KEY AUTOINCREMENT"
+}
+
+// boolLiteral uses the TRUE and FALSE keywords (SQLite 3.23+). SQLite keeps
+// the default as written, and GORM compares it with a default:false tag, so
+// DEFAULT 0 would make AutoMigrate rebuild the table.
+func (sqliteGrammar) boolLiteral(v bool) string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
+
+func (sqliteGrammar) compileAddForeign(table string, f *ForeignKey) ([]string, error) {
+	return nil, fmt.Errorf("schema: sqlite cannot add foreign key %s to existing table %s, declare it in schema.Create", f.name, table)
+}
+
+func (sqliteGrammar) compileDropForeign(table, name string) ([]string, error) {
+	return nil, fmt.Errorf("schema: sqlite cannot drop foreign key %s from table %s", name, table)
+}
+
+func (g sqliteGrammar) compilePrimary(table string, columns []string) string {
+	// sqlite cannot add a primary key to an existing table; a unique index
+	// gives the same guarantees for lookups and conflicts
+	return createIndex(g.quote, table, "pk_"+table, columns, true)
+}
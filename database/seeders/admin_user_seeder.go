@@ -0,0 +1,31 @@
+package seeders
+
+import (
+	"gomen/app/models"
+	"gomen/helpers"
+
+	"gorm.io/gorm"
+)
+
+// AdminUserSeeder creates the default admin account
+type AdminUserSeeder struct{}
+
+func init() {
+	Register(&AdminUserSeeder{})
+}
+
+func (s *AdminUserSeeder) Run(db *gorm.DB) error {
+	hashedPassword, err := helpers.HashPassword("password123")
+	if err != nil {
+		return err
+	}
+
+	admin := models.User{
+		Name:     "Admin",
+		Email:    "admin@example.com",
+		Password: hashedPassword,
+		IsActive: true,
+	}
+
+	return db.FirstOrCreate(&admin, models.User{Email: admin.Email}).Error
+}
@@ -0,0 +1,18 @@
+package seeders
+
+import (
+	"gorm.io/gorm"
+)
+
+// DatabaseSeeder is the seeder run by `gomen seed`
+type DatabaseSeeder struct{}
+
+func init() {
+	Register(&DatabaseSeeder{})
+}
+
+// Run calls every registered seeder in file name order. Replace it with an
+// explicit Call(db, &AdminUserSeeder{}, ...) list when seeders depend on each other.
+func (s *DatabaseSeeder) Run(db *gorm.DB) error {
+	return Call(db, All()...)
+}
@@ -0,0 +1,39 @@
+package seeders
+
+import (
+	"reflect"
+)
+
+var registered []Seeder
+
+// Register adds seeders to the registry under their type name (e.g.
+// ProductSeeder). Seeder files call it from init(), so new seeders can be
+// run without editing seeder.go.
+func Register(seeders ...Seeder) {
+	registered = append(registered, seeders...)
+}
+
+// All returns the registered seeders in file name order, except DatabaseSeeder
+func All() []Seeder {
+	var seeders []Seeder
+	for _, s := range registered {
+		if _, ok := s.(*DatabaseSeeder); !ok {
+			seeders = append(seeders, s)
+		}
+	}
+	return seeders
+}
+
+// Find returns the registered seeder with the given name
+func Find(name string) (Seeder, bool) {
+	for _, s := range registered {
+		if nameOf(s) == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func nameOf(s Seeder) string {
+	return reflect.Indirect(reflect.ValueOf(s)).Type().Name()
+}
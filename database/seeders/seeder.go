@@ -1,35 +1,54 @@
 package seeders
 
 import (
-	"gomen/app/models"
+	"fmt"
 	"gomen/config"
-	"gomen/helpers"
 	"log"
+	"time"
+
+	"gorm.io/gorm"
 )
 
-func Seed() {
+// Seeder populates the database
+type Seeder interface {
+	Run(db *gorm.DB) error
+}
+
+// Seed runs DatabaseSeeder
+func Seed() error {
+	return SeedClass("DatabaseSeeder")
+}
+
+// SeedClass runs a single registered seeder, e.g. SeedClass("ProductSeeder")
+func SeedClass(name string) error {
+	seeder, ok := Find(name)
+	if !ok {
+		return fmt.Errorf("seeder not found: %s", name)
+	}
+
 	log.Println("Running database seeders...")
 
-	// Seed admin user
-	seedAdminUser()
+	if err := Call(config.GetDB(), seeder); err != nil {
+		return fmt.Errorf("seeding failed: %w", err)
+	}
 
 	log.Println("Database seeding completed successfully")
+	return nil
 }
 
-func seedAdminUser() {
-	db := config.GetDB()
-	hashedPassword, _ := helpers.HashPassword("password123")
+// Call runs seeders in order and stops at the first error. Use it from a
+// seeder's Run method to run other seeders.
+func Call(db *gorm.DB, seeders ...Seeder) error {
+	for _, seeder := range seeders {
+		name := nameOf(seeder)
+		start := time.Now()
 
-	admin := models.User{
-		Name:     "Admin",
-		Email:    "admin@example.com",
-		Password: hashedPassword,
-		IsActive: true,
+		log.Printf("Seeding: %s", name)
+		if err := seeder.Run(db); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		log.Printf("Seeded: %s (%s)", name, time.Since(start).Round(time.Millisecond))
 	}
 
-	if err := db.FirstOrCreate(&admin, models.User{Email: admin.Email}).Error; err != nil {
-		log.Printf("Failed to seed admin user: %v", err)
-	} else {
-		log.Println("Admin user seeded successfully")
-	}
+	return nil
 }
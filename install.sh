@@ -42,6 +42,9 @@ cd "$SCRIPT_DIR"
 # Get dependencies
 go mod tidy 2>/dev/null
 
+# Embed the project skeleton for `gomen new`
+go generate ./internal/scaffold
+
 if go build -o gomen ./cmd/gomen; then
     echo -e "${GREEN}✓ CLI built successfully${NC}\n"
 else
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MakeCommand generates a console command in app/console/commands. The name
+// is either the struct name, e.g. PruneUsers, which runs as app:prune-users
+// unless command is given, or the command itself, e.g. users:prune.
+func MakeCommand(name, command string) {
+	ctx := commandContext(name)
+	ctx.Command = command
+	if ctx.Command == "" {
+		ctx.Command = defaultCommandName(name)
+	}
+
+	content, err := render("command", ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	filePath := filepath.Join(getProjectRoot(), "app", "console", "commands", ctx.Snake+".go")
+
+	if err := writeFile("Command", filePath, content); err != nil {
+		printError(err)
+		return
+	}
+
+	fmt.Printf("  → Run it with: gomen %s\n", ctx.Command)
+}
+
+// DestroyCommand deletes a generated console command, given by its struct
+// name or by the name it runs with
+func DestroyCommand(name string) {
+	dir := filepath.Join(getProjectRoot(), "app", "console", "commands")
+
+	// users:prune may live in prune_users.go
+	if strings.Contains(name, ":") {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err == nil && strings.Contains(string(content), "Name() string { return "+strconv.Quote(name)+" }") {
+				destroyFile("Command", filepath.Join(dir, entry.Name()))
+				return
+			}
+		}
+	}
+
+	destroyFile("Command", filepath.Join(dir, commandContext(name).Snake+".go"))
+}
+
+// commandContext names a command after users:prune or PruneUsers(Command)
+func commandContext(name string) Context {
+	name = strings.TrimSuffix(name, "Command")
+	return newContext(toSnakeCase(strings.NewReplacer(":", "_", "-", "_").Replace(name)))
+}
+
+// defaultCommandName returns name when it already looks like a command, and
+// app:prune-users for PruneUsers
+func defaultCommandName(name string) string {
+	if strings.Contains(name, ":") {
+		return name
+	}
+	return "app:" + strings.ReplaceAll(commandContext(name).Snake, "_", "-")
+}
@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AppliedMigrations returns the migrations recorded in schema_migrations,
+// e.g. 20240101120000_create_products_table. The CLI asks the application
+// binary; when nil, migrations are deleted without checking.
+var AppliedMigrations func() (map[string]bool, error)
+
+// DestroyResource removes what make:resource generated for name: its routes
+// are unwired from routes/api.go and the files that were not changed since
+// they were generated are deleted. Deleting the model and migration files
+// also removes them from AutoMigrate and the migrator. Nothing is deleted
+// while its migration is applied, unless --force is given.
+func DestroyResource(name string) {
+	ctx := newContext(name)
+	root := getProjectRoot()
+
+	fmt.Printf("\n🧹 Destroying resource: %s\n\n", ctx.Pascal)
+
+	migrations := migrationFiles("create_" + ctx.Table + "_table")
+	applied, ok := checkMigrations(migrations)
+	if !ok {
+		return
+	}
+
+	path, before, after, err := unwireRoutes(root, ctx.Pascal)
+	if err != nil {
+		printError(err)
+	} else if err := applyWiring(root, path, before, after, true); err != nil {
+		printError(err)
+	}
+
+	DestroyController(name)
+	DestroyService(name)
+	DestroyRequest(name)
+	DestroyModel(name)
+	DestroyFactory(name)
+	DestroySeeder(name)
+	destroyMigrations(migrations, applied)
+
+	switch {
+	case Failed():
+		fmt.Println("\n\033[31m✗\033[0m Some files were kept, see above")
+	case options.DryRun:
+		fmt.Println("\n✨ Dry run finished, nothing was deleted")
+	default:
+		fmt.Println("\n✨ Resource destroyed successfully!")
+	}
+}
+
+// DestroyController deletes a generated controller
+func DestroyController(name string) {
+	ctx := newContext(name)
+	destroyFile("Controller", filepath.Join(getProjectRoot(), "app", "controllers", ctx.Snake+"_controller.go"))
+}
+
+// DestroyModel deletes a generated model
+func DestroyModel(name string) {
+	ctx := newContext(name)
+	destroyFile("Model", filepath.Join(getProjectRoot(), "app", "models", ctx.Snake+".go"))
+}
+
+// DestroyService deletes a generated service
+func DestroyService(name string) {
+	ctx := newContext(name)
+	destroyFile("Service", filepath.Join(getProjectRoot(), "app", "services", ctx.Snake+"_service.go"))
+}
+
+// DestroyRequest deletes a generated request
+func DestroyRequest(name string) {
+	ctx := newContext(name)
+	destroyFile("Request", filepath.Join(getProjectRoot(), "app", "requests", ctx.Snake+"_request.go"))
+}
+
+// DestroyMiddleware deletes a generated middleware
+func DestroyMiddleware(name string) {
+	ctx := newContext(name)
+	destroyFile("Middleware", filepath.Join(getProjectRoot(), "app", "middlewares", ctx.Snake+".go"))
+}
+
+// DestroyFactory deletes a generated factory
+func DestroyFactory(name string) {
+	ctx := newContext(strings.TrimSuffix(name, "Factory"))
+	destroyFile("Factory", filepath.Join(getProjectRoot(), "database", "factories", ctx.Snake+"_factory.go"))
+}
+
+// DestroySeeder deletes a generated seeder
+func DestroySeeder(name string) {
+	ctx := newContext(strings.TrimSuffix(name, "Seeder"))
+	destroyFile("Seeder", filepath.Join(getProjectRoot(), "database", "seeders", ctx.Snake+"_seeder.go"))
+}
+
+// DestroyMigration deletes the generated migrations with the given name,
+// e.g. create_products_table, whatever their timestamp
+func DestroyMigration(name string) {
+	snakeName := toSnakeCase(name)
+
+	files := migrationFiles(snakeName)
+	if len(files) == 0 {
+		printError(fmt.Errorf("no migration named %s found in database/migrations", snakeName))
+		return
+	}
+
+	applied, ok := checkMigrations(files)
+	if !ok {
+		return
+	}
+	destroyMigrations(files, applied)
+}
+
+// migrationName returns the name of a migration file as recorded in
+// schema_migrations, e.g. 20240101120000_create_products_table
+func migrationName(filePath string) string {
+	return strings.TrimSuffix(filepath.Base(filePath), ".go")
+}
+
+// checkMigrations returns which of the migration files already ran. A
+// migration that ran cannot be rolled back once its file is gone, so ok is
+// false when one ran or the database cannot be asked, unless --force.
+func checkMigrations(files []string) (applied map[string]bool, ok bool) {
+	if len(files) == 0 || AppliedMigrations == nil {
+		return nil, true
+	}
+
+	applied, err := AppliedMigrations()
+	if err != nil {
+		if options.Force {
+			fmt.Printf("\033[33m•\033[0m Could not check which migrations ran: %s\n", err)
+			return nil, true
+		}
+		printError(fmt.Errorf("could not check which migrations ran, kept everything: %w (use --force to delete anyway)", err))
+		return nil, false
+	}
+
+	ok = true
+	for _, filePath := range files {
+		if applied[migrationName(filePath)] && !options.Force {
+			printError(fmt.Errorf("migration %s already ran, kept everything: roll it back with gomen migrate:rollback first (use --force to delete anyway)",
+				migrationName(filePath)))
+			ok = false
+		}
+	}
+	return applied, ok
+}
+
+// destroyMigrations deletes migration files. For the ones that ran, which
+// only happens with --force, it tells how to forget them in schema_migrations.
+func destroyMigrations(files []string, applied map[string]bool) {
+	for _, filePath := range files {
+		destroyFile("Migration", filePath)
+
+		name := migrationName(filePath)
+		if !applied[name] || options.DryRun {
+			continue
+		}
+		if _, err := os.Stat(filePath); err == nil {
+			continue
+		}
+		fmt.Printf("  → %s already ran: its tables are still there and migrate:rollback will fail on its batch until you run\n", name)
+		fmt.Printf("    DELETE FROM schema_migrations WHERE migration = '%s';\n", name)
+	}
+}
+
+// migrationFiles returns the migration files named <timestamp>_<snakeName>.go
+func migrationFiles(snakeName string) []string {
+	pattern := regexp.MustCompile(`^\d{14}_` + regexp.QuoteMeta(snakeName) + `\.go$`)
+
+	entries, err := os.ReadDir(filepath.Join(getProjectRoot(), "database", "migrations"))
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if pattern.MatchString(entry.Name()) {
+			files = append(files, filepath.Join(getProjectRoot(), "database", "migrations", entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// destroyFile deletes a file if its checksum matches the one recorded when
+// it was generated. --force deletes changed and hand-written files too.
+func destroyFile(fileType, filePath string) {
+	m, err := loadManifest()
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	key := manifestKey(filePath)
+	entry, generated := m.Files[key]
+
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		fmt.Printf("\033[33m•\033[0m %s not found: %s\n", fileType, filePath)
+		if generated && !options.DryRun {
+			delete(m.Files, key)
+			if err := m.save(); err != nil {
+				printError(err)
+			}
+		}
+		return
+	}
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	switch {
+	case options.Force:
+	case !generated:
+		printError(fmt.Errorf("%s was not generated by gomen, kept it (use --force to delete it anyway)", filePath))
+		return
+	case checksum(content) != entry.SHA256:
+		printError(fmt.Errorf("%s changed since it was generated, kept it (use --force to delete it anyway)", filePath))
+		return
+	}
+
+	if options.DryRun {
+		fmt.Printf("\033[36m•\033[0m %s would be deleted: %s\n", fileType, filePath)
+		return
+	}
+
+	if err := os.Remove(filePath); err != nil {
+		printError(fmt.Errorf("failed to delete file: %w", err))
+		return
+	}
+
+	if generated {
+		delete(m.Files, key)
+		if err := m.save(); err != nil {
+			printError(err)
+		}
+	}
+
+	fmt.Printf("\033[32m✓\033[0m %s deleted: %s\n", fileType, filePath)
+}
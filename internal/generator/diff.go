@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// unifiedDiff returns the changes from before to after in unified diff
+// format, or "" when they are equal
+func unifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+
+	a := splitLines(before)
+	b := splitLines(after)
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+
+	// Group the operations into hunks with diffContext lines around changes
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+		to := start
+		for to < len(ops) {
+			if ops[to].kind != ' ' {
+				to++
+				continue
+			}
+			// Stop when the next change is further than two contexts away
+			next := to
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-to > 2*diffContext {
+				to += diffContext
+				if to > len(ops) {
+					to = len(ops)
+				}
+				break
+			}
+			to = next
+		}
+
+		oldStart, newStart := ops[from].oldLine, ops[from].newLine
+		oldCount, newCount := 0, 0
+		var hunk strings.Builder
+		for _, op := range ops[from:to] {
+			switch op.kind {
+			case ' ':
+				oldCount++
+				newCount++
+			case '-':
+				oldCount++
+			case '+':
+				newCount++
+			}
+			fmt.Fprintf(&hunk, "%c%s\n", op.kind, op.text)
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n%s", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount), hunk.String())
+		start = to
+	}
+
+	return out.String()
+}
+
+// diffOp is one line of a diff: ' ' kept, '-' removed or '+' added.
+// oldLine and newLine are the 1-based positions the line starts at.
+type diffOp struct {
+	kind             byte
+	text             string
+	oldLine, newLine int
+}
+
+// diffLines computes a line diff from the longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removed lines come before the lines that replace them
+			ops = append(ops, diffOp{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// printDiff prints a diff with added lines in green and removed lines in red
+func printDiff(diff string) {
+	for _, line := range splitLines(diff) {
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+			fmt.Printf("\033[1m%s\033[0m\n", line)
+		case strings.HasPrefix(line, "+"):
+			fmt.Printf("\033[32m%s\033[0m\n", line)
+		case strings.HasPrefix(line, "-"):
+			fmt.Printf("\033[31m%s\033[0m\n", line)
+		case strings.HasPrefix(line, "@@"):
+			fmt.Printf("\033[36m%s\033[0m\n", line)
+		default:
+			fmt.Println(line)
+		}
+	}
+}
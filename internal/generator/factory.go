@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// MakeFactory generates a factory for a model in app/models
+func MakeFactory(name string) {
+	ctx := newContext(strings.TrimSuffix(name, "Factory"))
+	root := getProjectRoot()
+
+	fields, err := modelFields(filepath.Join(root, "app", "models", ctx.Snake+".go"), ctx.Pascal)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	for _, field := range fields {
+		ctx.Values = append(ctx.Values, FactoryValue{Field: field.name, Value: fakeValue(ctx.Pascal, field)})
+	}
+
+	content, err := render("factory", ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	filePath := filepath.Join(root, "database", "factories", ctx.Snake+"_factory.go")
+
+	if err := writeFile("Factory", filePath, content); err != nil {
+		printError(err)
+		return
+	}
+}
+
+// modelField is a field of a model struct as written in its source file
+type modelField struct {
+	name   string
+	goType string
+}
+
+// modelFields reads the exported, non-embedded fields of a model struct
+func modelFields(filePath, structName string) ([]modelField, error) {
+	src, err := readFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("model not found, run make:model first: %w", err)
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []modelField
+	found := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != structName {
+			return true
+		}
+
+		structType, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		found = true
+		for _, field := range structType.Fields.List {
+			for _, name := range field.Names {
+				if name.IsExported() {
+					fields = append(fields, modelField{name: name.Name, goType: exprString(field.Type)})
+				}
+			}
+		}
+		return false
+	})
+
+	if !found {
+		return nil, fmt.Errorf("struct %s not found in %s", structName, filePath)
+	}
+
+	return fields, nil
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(e.Elt)
+	default:
+		return ""
+	}
+}
+
+// fakeValue picks a Faker call for a field from its name and type. It
+// returns "" for fields that cannot be faked, such as foreign keys.
+func fakeValue(model string, field modelField) string {
+	name := strings.ToLower(field.name)
+
+	switch field.goType {
+	case "string":
+		switch {
+		case strings.Contains(name, "email"):
+			return "f.Email()"
+		case strings.Contains(name, "password"):
+			return "password()"
+		case strings.Contains(name, "phone"):
+			return "f.Phone()"
+		case strings.Contains(name, "url") || strings.Contains(name, "website"):
+			return "f.URL()"
+		case strings.Contains(name, "slug"):
+			return "f.Slug(3)"
+		case name == "username":
+			return "f.Username()"
+		case strings.HasSuffix(name, "firstname"):
+			return "f.FirstName()"
+		case strings.HasSuffix(name, "lastname"):
+			return "f.LastName()"
+		case name == "name" && model == "User":
+			return "f.Name()"
+		case name == "name" || name == "title":
+			return "f.Title()"
+		case strings.Contains(name, "description") || strings.Contains(name, "content") || strings.Contains(name, "body"):
+			return "f.Paragraph()"
+		default:
+			return "f.Word()"
+		}
+
+	case "int":
+		return "f.Int(0, 100)"
+
+	case "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		// Unsigned ID fields are foreign keys (CategoryID)
+		if strings.HasPrefix(field.goType, "uint") && strings.HasSuffix(field.name, "ID") {
+			return ""
+		}
+		return field.goType + "(f.Int(0, 100))"
+
+	case "float64":
+		if strings.Contains(name, "price") || strings.Contains(name, "amount") {
+			return "f.Float(1000, 1000000, 2)"
+		}
+		return "f.Float(0, 100, 2)"
+
+	case "float32":
+		return "float32(f.Float(0, 100, 2))"
+
+	case "bool":
+		if strings.HasPrefix(name, "is") && strings.Contains(name, "active") {
+			return "true"
+		}
+		return "f.Bool(50)"
+
+	case "time.Time":
+		return "f.PastTime()"
+	}
+
+	return ""
+}
@@ -0,0 +1,253 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Field is a model field given on the command line as name:type[:rules],
+// e.g. name:string:required,max=100 or category_id:fk:categories
+type Field struct {
+	Column string
+	Type   string
+	// Rules are validator tags, e.g. "required,max=100"
+	Rules string
+	// References is the table a fk field points to
+	References string
+}
+
+// fieldTypes are the types accepted by ParseFields
+var fieldTypes = map[string]bool{
+	"string": true, "text": true, "int": true, "uint": true, "decimal": true,
+	"float": true, "bool": true, "date": true, "datetime": true, "fk": true,
+}
+
+var (
+	columnPattern  = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	tablePattern   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	maxRulePattern = regexp.MustCompile(`(?:^|,)max=(\d+)`)
+)
+
+// ParseFields parses field definitions such as price:decimal or
+// category_id:fk:categories:required
+func ParseFields(specs []string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid field %q, expected name:type[:rules]", spec)
+		}
+
+		field := Field{Column: toSnakeCase(parts[0]), Type: parts[1]}
+		if len(parts) == 3 {
+			field.Rules = parts[2]
+		}
+
+		if !columnPattern.MatchString(field.Column) {
+			return nil, fmt.Errorf("invalid field name %q", parts[0])
+		}
+		if !fieldTypes[field.Type] {
+			return nil, fmt.Errorf("unknown type %q for field %s, use one of: string, text, int, uint, decimal, float, bool, date, datetime, fk", field.Type, field.Column)
+		}
+		switch field.Column {
+		case "id", "created_at", "updated_at", "deleted_at":
+			return nil, fmt.Errorf("field %s is already part of BaseModel", field.Column)
+		}
+		if seen[field.Column] {
+			return nil, fmt.Errorf("duplicate field %s", field.Column)
+		}
+		seen[field.Column] = true
+
+		if field.Type == "fk" {
+			// The third part is the referenced table, rules may follow it
+			refs := strings.SplitN(field.Rules, ":", 2)
+			field.References, field.Rules = refs[0], ""
+			if len(refs) == 2 {
+				field.Rules = refs[1]
+			}
+			if field.References == "" {
+				return nil, fmt.Errorf("field %s needs the referenced table, e.g. %s:fk:categories", field.Column, field.Column)
+			}
+			if !tablePattern.MatchString(field.References) {
+				return nil, fmt.Errorf("invalid table %q referenced by field %s", field.References, field.Column)
+			}
+			if field.Rules == "" {
+				field.Rules = "required"
+			}
+		}
+
+		// Rules end up in a struct tag
+		if strings.ContainsAny(field.Rules, "\"`\n") {
+			return nil, fmt.Errorf("invalid rules %q for field %s", field.Rules, field.Column)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Name is the Go field name (category_id becomes CategoryID)
+func (f Field) Name() string {
+	name := toPascalCase(f.Column)
+	if strings.HasSuffix(name, "Id") {
+		name = strings.TrimSuffix(name, "Id") + "ID"
+	}
+	return name
+}
+
+// required reports whether the field must be present
+func (f Field) required() bool {
+	for _, rule := range strings.Split(f.Rules, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// size is the VARCHAR length of a string field, taken from its max rule
+func (f Field) size() int {
+	if matches := maxRulePattern.FindStringSubmatch(f.Rules); matches != nil {
+		if size, err := strconv.Atoi(matches[1]); err == nil && size > 0 {
+			return size
+		}
+	}
+	return 255
+}
+
+// GoType is the type of the field in the model and request structs.
+// Optional dates are pointers so they can be NULL.
+func (f Field) GoType() string {
+	switch f.Type {
+	case "string", "text":
+		return "string"
+	case "int":
+		return "int"
+	case "uint", "fk":
+		return "uint"
+	case "decimal", "float":
+		return "float64"
+	case "bool":
+		return "bool"
+	default: // date, datetime
+		if f.required() {
+			return "time.Time"
+		}
+		return "*time.Time"
+	}
+}
+
+// gormTag is the gorm struct tag. It declares the same type, size,
+// nullability and default as ColumnCall, so on SQLite AutoMigrate finds
+// nothing to change in the migrated table (see migrate --pretend).
+func (f Field) gormTag() string {
+	switch f.Type {
+	case "string":
+		return fmt.Sprintf("size:%d;not null", f.size())
+	case "text":
+		if f.required() {
+			return "type:text;not null"
+		}
+		return "type:text"
+	case "int", "uint", "float":
+		return "not null;default:0"
+	case "decimal":
+		return "precision:10;scale:2;not null;default:0"
+	case "bool":
+		return "not null;default:false"
+	case "fk":
+		return "not null;index"
+	case "date":
+		if f.required() {
+			return "type:date;not null"
+		}
+		return "type:date"
+	default: // datetime
+		if f.required() {
+			return "not null"
+		}
+		return ""
+	}
+}
+
+// ModelTag is the struct tag of the field in a model
+func (f Field) ModelTag() string {
+	tag := fmt.Sprintf(`json:"%s"`, f.Column)
+	if gorm := f.gormTag(); gorm != "" {
+		tag += fmt.Sprintf(` gorm:"%s"`, gorm)
+	}
+	return tag
+}
+
+// RequestTag is the struct tag of the field in a request. Strings get a max
+// rule matching their column length.
+func (f Field) RequestTag() string {
+	rules := f.Rules
+	if f.Type == "string" && !maxRulePattern.MatchString(rules) {
+		rules = strings.TrimPrefix(rules+",max=255", ",")
+	}
+
+	tag := fmt.Sprintf(`json:"%s"`, f.Column)
+	if rules != "" {
+		tag += fmt.Sprintf(` validate:"%s"`, rules)
+	}
+	return tag
+}
+
+// VarName is the field name as a local variable (CategoryID becomes categoryID)
+func (f Field) VarName() string {
+	name := f.Name()
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// ColumnCall renders the schema.Blueprint call that creates the column
+func (f Field) ColumnCall() string {
+	var call string
+	switch f.Type {
+	case "string":
+		call = fmt.Sprintf("t.String(%q, %d)", f.Column, f.size())
+	case "text":
+		call = fmt.Sprintf("t.Text(%q)", f.Column)
+		if !f.required() {
+			call += ".Nullable()"
+		}
+	case "int":
+		call = fmt.Sprintf("t.BigInteger(%q).Default(0)", f.Column)
+	case "uint":
+		call = fmt.Sprintf("t.BigInteger(%q).Unsigned().Default(0)", f.Column)
+	case "decimal":
+		call = fmt.Sprintf("t.Decimal(%q, 10, 2).Default(0)", f.Column)
+	case "float":
+		call = fmt.Sprintf("t.Double(%q).Default(0)", f.Column)
+	case "bool":
+		call = fmt.Sprintf("t.Boolean(%q).Default(false)", f.Column)
+	case "fk":
+		call = fmt.Sprintf("t.ForeignID(%q).Index()", f.Column)
+	case "date":
+		call = fmt.Sprintf("t.Date(%q)", f.Column)
+		if !f.required() {
+			call += ".Nullable()"
+		}
+	default: // datetime
+		call = fmt.Sprintf("t.DateTime(%q)", f.Column)
+		if !f.required() {
+			call += ".Nullable()"
+		}
+	}
+	return call
+}
+
+// usesTime reports whether any of fields needs the time package
+func usesTime(fields []Field) bool {
+	for _, field := range fields {
+		if strings.HasSuffix(field.GoType(), "time.Time") {
+			return true
+		}
+	}
+	return false
+}
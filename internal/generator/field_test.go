@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []Field
+		wantErr bool
+	}{
+		{name: "none", specs: nil, want: nil},
+		{
+			name:  "types and rules",
+			specs: []string{"name:string:required,max=100", "price:decimal", "active:bool:"},
+			want: []Field{
+				{Column: "name", Type: "string", Rules: "required,max=100"},
+				{Column: "price", Type: "decimal"},
+				{Column: "active", Type: "bool"},
+			},
+		},
+		{
+			name:  "rules with colons",
+			specs: []string{"starts_at:datetime:required,datetime=15:04"},
+			want:  []Field{{Column: "starts_at", Type: "datetime", Rules: "required,datetime=15:04"}},
+		},
+		{
+			name:  "camel case name",
+			specs: []string{"unitPrice:float"},
+			want:  []Field{{Column: "unit_price", Type: "float"}},
+		},
+		{
+			name:  "foreign key",
+			specs: []string{"category_id:fk:categories"},
+			want:  []Field{{Column: "category_id", Type: "fk", Rules: "required", References: "categories"}},
+		},
+		{
+			name:  "foreign key with rules",
+			specs: []string{"category_id:fk:categories:omitempty"},
+			want:  []Field{{Column: "category_id", Type: "fk", Rules: "omitempty", References: "categories"}},
+		},
+		{name: "missing type", specs: []string{"name"}, wantErr: true},
+		{name: "empty type", specs: []string{"name:"}, wantErr: true},
+		{name: "empty name", specs: []string{":string"}, wantErr: true},
+		{name: "unknown type", specs: []string{"name:varchar"}, wantErr: true},
+		{name: "name starting with a digit", specs: []string{"1st:int"}, wantErr: true},
+		{name: "name with a dash", specs: []string{"first-name:string"}, wantErr: true},
+		{name: "base model field", specs: []string{"created_at:datetime"}, wantErr: true},
+		{name: "id", specs: []string{"id:uint"}, wantErr: true},
+		{name: "duplicate", specs: []string{"name:string", "name:text"}, wantErr: true},
+		{name: "duplicate after snake case", specs: []string{"unit_price:float", "unitPrice:decimal"}, wantErr: true},
+		{name: "foreign key without table", specs: []string{"category_id:fk"}, wantErr: true},
+		{name: "foreign key with empty table", specs: []string{"category_id:fk::required"}, wantErr: true},
+		{name: "foreign key with invalid table", specs: []string{`category_id:fk:categories"`}, wantErr: true},
+		{name: "quote in rules", specs: []string{`name:string:required"`}, wantErr: true},
+		{name: "backtick in rules", specs: []string{"name:string:required`"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFields(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFields(%q) error = %v, wantErr %v", tt.specs, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFields(%q) =\n%+v\nwant\n%+v", tt.specs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldCode(t *testing.T) {
+	tests := []struct {
+		spec       string
+		name       string
+		goType     string
+		requestTag string
+		columnCall string
+	}{
+		{"name:string", "Name", "string", `json:"name" validate:"max=255"`, `t.String("name", 255)`},
+		{"title:string:required,max=100", "Title", "string", `json:"title" validate:"required,max=100"`, `t.String("title", 100)`},
+		{"body:text", "Body", "string", `json:"body"`, `t.Text("body").Nullable()`},
+		{"stock:int:min=0", "Stock", "int", `json:"stock" validate:"min=0"`, `t.BigInteger("stock").Default(0)`},
+		{"price:decimal:required", "Price", "float64", `json:"price" validate:"required"`, `t.Decimal("price", 10, 2).Default(0)`},
+		{"published_on:date", "PublishedOn", "*time.Time", `json:"published_on"`, `t.Date("published_on").Nullable()`},
+		{"starts_at:datetime:required", "StartsAt", "time.Time", `json:"starts_at" validate:"required"`, `t.DateTime("starts_at")`},
+		{"category_id:fk:categories", "CategoryID", "uint", `json:"category_id" validate:"required"`, `t.ForeignID("category_id").Index()`},
+	}
+
+	for _, tt := range tests {
+		fields, err := ParseFields([]string{tt.spec})
+		if err != nil {
+			t.Fatalf("ParseFields(%q): %v", tt.spec, err)
+		}
+		field := fields[0]
+
+		if got := field.Name(); got != tt.name {
+			t.Errorf("%s: Name() = %q, want %q", tt.spec, got, tt.name)
+		}
+		if got := field.GoType(); got != tt.goType {
+			t.Errorf("%s: GoType() = %q, want %q", tt.spec, got, tt.goType)
+		}
+		if got := field.RequestTag(); got != tt.requestTag {
+			t.Errorf("%s: RequestTag() = %q, want %q", tt.spec, got, tt.requestTag)
+		}
+		if got := field.ColumnCall(); got != tt.columnCall {
+			t.Errorf("%s: ColumnCall() = %q, want %q", tt.spec, got, tt.columnCall)
+		}
+	}
+}
@@ -2,12 +2,16 @@ package generator
 
 import (
 	"fmt"
+	"gomen/internal/scaffold"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"unicode"
 )
 
+var modulePattern = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
 // toSnakeCase converts PascalCase or camelCase to snake_case
 func toSnakeCase(s string) string {
 	var result strings.Builder
@@ -58,21 +62,118 @@ func toPlural(s string) string {
 	return s + "s"
 }
 
-// writeFile writes content to a file, creating directories if needed
-func writeFile(filePath, content string) error {
+// modulePath returns the module path declared in the project's go.mod
+func modulePath() string {
+	content, err := os.ReadFile(filepath.Join(getProjectRoot(), "go.mod"))
+	if err != nil {
+		return scaffold.Module
+	}
+
+	if matches := modulePattern.FindSubmatch(content); matches != nil {
+		return string(matches[1])
+	}
+	return scaffold.Module
+}
+
+// Options change how the generators write files
+type Options struct {
+	// DryRun prints the files instead of writing them
+	DryRun bool
+	// Force overwrites existing files after showing the diff
+	Force bool
+}
+
+var (
+	options Options
+	// pending holds the files of a dry run, so later generators can read
+	// the files earlier ones would have written
+	pending = map[string][]byte{}
+	// failures counts the errors reported by printError
+	failures int
+)
+
+// SetOptions sets the options of the following generator calls
+func SetOptions(opts Options) {
+	options = opts
+}
+
+// Failed reports whether a generator reported an error
+func Failed() bool {
+	return failures > 0
+}
+
+// readFile reads a file, or the content a dry run would have written to it
+func readFile(filePath string) ([]byte, error) {
+	if content, ok := pending[filePath]; ok {
+		return content, nil
+	}
+	return os.ReadFile(filePath)
+}
+
+// fileExists reports whether a file exists or a dry run would have written it
+func fileExists(filePath string) bool {
+	_, err := readFile(filePath)
+	return err == nil
+}
+
+// writeFile writes content to a file, creating directories if needed, and
+// reports it as fileType. Existing files are only replaced with --force.
+// The checksum of the file is recorded for destroy:*.
+// Imports in Go files follow the module path of the project.
+func writeFile(fileType, filePath, content string) error {
+	if filepath.Ext(filePath) == ".go" {
+		content = scaffold.RewriteImports(content, scaffold.Module, modulePath())
+	}
+
+	existing, err := readFile(filePath)
+	exists := err == nil
+	if exists && !options.Force {
+		return fmt.Errorf("file already exists: %s (use --force to overwrite)", filePath)
+	}
+	if exists && string(existing) == content {
+		fmt.Printf("\033[33m•\033[0m %s unchanged: %s\n", fileType, filePath)
+		if options.DryRun {
+			return nil
+		}
+		return recordGenerated(fileType, filePath, content)
+	}
+
+	var diff string
+	if exists {
+		relative, _ := filepath.Rel(getProjectRoot(), filePath)
+		diff = unifiedDiff(filepath.ToSlash(relative), string(existing), content)
+	}
+
+	if options.DryRun {
+		pending[filePath] = []byte(content)
+		if exists {
+			fmt.Printf("\033[36m•\033[0m %s would be overwritten: %s\n", fileType, filePath)
+			printDiff(diff)
+		} else {
+			fmt.Printf("\033[36m•\033[0m %s would be created: %s\n\n%s\n", fileType, filePath, content)
+		}
+		return nil
+	}
+
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	if _, err := os.Stat(filePath); err == nil {
-		return fmt.Errorf("file already exists: %s", filePath)
-	}
-
 	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	if err := recordGenerated(fileType, filePath, content); err != nil {
+		return err
+	}
+
+	if exists {
+		fmt.Printf("\033[32m✓\033[0m %s overwritten: %s\n", fileType, filePath)
+		printDiff(diff)
+		return nil
+	}
 
+	printSuccess(fileType, filePath)
 	return nil
 }
 
@@ -83,6 +184,7 @@ func printSuccess(fileType, filePath string) {
 
 // printError prints an error message
 func printError(err error) {
+	failures++
 	fmt.Printf("\033[31m✗\033[0m Error: %s\n", err)
 }
 
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// manifestPath is where the checksums of the generated files are kept,
+// relative to the project root. destroy:* only deletes files whose content
+// still matches their checksum.
+var manifestPath = filepath.Join(".gomen", "generated.json")
+
+// manifest lists the generated files by their slash-separated path relative
+// to the project root
+type manifest struct {
+	Files map[string]manifestEntry `json:"files"`
+}
+
+type manifestEntry struct {
+	Type   string `json:"type"`
+	SHA256 string `json:"sha256"`
+}
+
+// loadManifest reads the manifest of the project, or returns an empty one
+func loadManifest() (*manifest, error) {
+	m := &manifest{Files: map[string]manifestEntry{}}
+
+	content, err := os.ReadFile(filepath.Join(getProjectRoot(), manifestPath))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestPath, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]manifestEntry{}
+	}
+	return m, nil
+}
+
+func (m *manifest) save() error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(getProjectRoot(), manifestPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return os.WriteFile(filePath, append(content, '\n'), 0644)
+}
+
+// recordGenerated stores the checksum of a file written by a generator
+func recordGenerated(fileType, filePath, content string) error {
+	m, err := loadManifest()
+	if err != nil {
+		return err
+	}
+
+	m.Files[manifestKey(filePath)] = manifestEntry{Type: fileType, SHA256: checksum([]byte(content))}
+
+	if err := m.save(); err != nil {
+		return fmt.Errorf("failed to update %s: %w", manifestPath, err)
+	}
+	return nil
+}
+
+// manifestKey is the path of a file as stored in the manifest
+func manifestKey(filePath string) string {
+	relative, err := filepath.Rel(getProjectRoot(), filePath)
+	if err != nil {
+		relative = filePath
+	}
+	return filepath.ToSlash(relative)
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -7,36 +7,20 @@ import (
 
 // MakeMiddleware generates a new middleware file
 func MakeMiddleware(name string) {
-	pascalName := toPascalCase(name)
-	snakeName := toSnakeCase(pascalName)
+	ctx := newContext(name)
 
-	content := fmt.Sprintf(`package middlewares
-
-import (
-	"github.com/gin-gonic/gin"
-)
-
-func %sMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Before request
-		// Add your middleware logic here
-
-		// Process request
-		c.Next()
-
-		// After request
-		// Add your post-processing logic here
+	content, err := render("middleware", ctx)
+	if err != nil {
+		printError(err)
+		return
 	}
-}
-`, pascalName)
 
-	filePath := filepath.Join(getProjectRoot(), "app", "middlewares", snakeName+".go")
+	filePath := filepath.Join(getProjectRoot(), "app", "middlewares", ctx.Snake+".go")
 
-	if err := writeFile(filePath, content); err != nil {
+	if err := writeFile("Middleware", filePath, content); err != nil {
 		printError(err)
 		return
 	}
 
-	printSuccess("Middleware", filePath)
 	fmt.Println("  → Don't forget to register this middleware in routes/api.go")
 }
@@ -2,58 +2,93 @@ package generator
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 )
 
-// MakeMigration generates a new migration file
-func MakeMigration(name string) {
+var (
+	createTablePattern = regexp.MustCompile(`^create_(\w+)_table$`)
+	alterTablePattern  = regexp.MustCompile(`_(?:to|from|in|on)_(\w+)_table$`)
+)
+
+// MakeMigration generates a new migration file. A create_*_table migration
+// gets a column for each of the given fields. The struct is named after the
+// migration, so an existing migration with the same name is only replaced,
+// keeping its timestamp, with --force.
+func MakeMigration(name string, fields ...Field) {
 	snakeName := toSnakeCase(name)
-	timestamp := time.Now().Format("20060102150405")
-	fileName := fmt.Sprintf("%s_%s", timestamp, snakeName)
+	dir := filepath.Join(getProjectRoot(), "database", "migrations")
+
+	fileName := fmt.Sprintf("%s_%s", time.Now().Format("20060102150405"), snakeName)
+	if existing := findMigration(dir, snakeName); existing != "" {
+		if !options.Force {
+			printError(fmt.Errorf("migration %s already exists: %s (use --force to overwrite)", snakeName, existing))
+			return
+		}
+		fileName = strings.TrimSuffix(filepath.Base(existing), ".go")
+	}
 
-	content := fmt.Sprintf(`package migrations
+	ctx := newContext(snakeName)
+	ctx.Migration = fileName
+	ctx.Fields = fields
 
-import (
-	"gorm.io/gorm"
-)
+	stub := "migration.update"
+	table, creating := guessTable(snakeName)
+	if creating {
+		stub = "migration.create"
+	}
+	ctx.Table = table
+
+	content, err := render(stub, ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
 
-// %s migration
-type %s struct{}
-
-func (m *%s) Up(db *gorm.DB) error {
-	// Example: Create table
-	// return db.Exec(`+"`"+`
-	// 	CREATE TABLE IF NOT EXISTS table_name (
-	// 		id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-	// 		name VARCHAR(255) NOT NULL,
-	// 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-	// 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-	// 		deleted_at TIMESTAMP NULL
-	// 	)
-	// `+"`"+`).Error
-
-	// Or use GORM AutoMigrate
-	// return db.AutoMigrate(&models.YourModel{})
-
-	return nil
+	filePath := filepath.Join(dir, fileName+".go")
+
+	if err := writeFile("Migration", filePath, content); err != nil {
+		printError(err)
+		return
+	}
 }
 
-func (m *%s) Down(db *gorm.DB) error {
-	// Example: Drop table
-	// return db.Exec("DROP TABLE IF EXISTS table_name").Error
+// findMigration returns the file of the migration named snakeName in dir,
+// e.g. 20251202190735_create_products_table.go, or "" when there is none
+func findMigration(dir, snakeName string) string {
+	pattern := regexp.MustCompile(`^\d{14}_` + regexp.QuoteMeta(snakeName) + `\.go$`)
 
-	return nil
+	var candidates []string
+	for path := range pending {
+		candidates = append(candidates, path)
+	}
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			candidates = append(candidates, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	for _, path := range candidates {
+		if filepath.Dir(path) == dir && pattern.MatchString(filepath.Base(path)) {
+			return path
+		}
+	}
+	return ""
 }
-`, toPascalCase(snakeName), toPascalCase(snakeName), toPascalCase(snakeName), toPascalCase(snakeName))
 
-	filePath := filepath.Join(getProjectRoot(), "database", "migrations", fileName+".go")
+// guessTable infers the table a migration works on from names such as
+// create_products_table or add_stock_to_products_table
+func guessTable(snakeName string) (table string, creating bool) {
+	if matches := createTablePattern.FindStringSubmatch(snakeName); matches != nil {
+		return matches[1], true
+	}
 
-	if err := writeFile(filePath, content); err != nil {
-		printError(err)
-		return
+	if matches := alterTablePattern.FindStringSubmatch(snakeName); matches != nil {
+		return matches[1], false
 	}
 
-	printSuccess("Migration", filePath)
-	fmt.Println("  → Register this migration in database/migrations/migrate.go")
+	return "table_name", false
 }
@@ -1,36 +1,24 @@
 package generator
 
 import (
-	"fmt"
 	"path/filepath"
 )
 
-// MakeModel generates a new model file
-func MakeModel(name string) {
-	pascalName := toPascalCase(name)
-	snakeName := toSnakeCase(pascalName)
-	tableName := toPlural(snakeName)
+// MakeModel generates a new model file, with the given fields or a single Name field
+func MakeModel(name string, fields ...Field) {
+	ctx := newContext(name)
+	ctx.Fields = fields
 
-	content := fmt.Sprintf(`package models
-
-type %s struct {
-	BaseModel
-	Name string `+"`json:\"name\" gorm:\"size:255;not null\"`"+`
-	// Add your fields here
-}
-
-func (%s) TableName() string {
-	return "%s"
-}
-`, pascalName, pascalName, tableName)
+	content, err := render("model", ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
 
-	filePath := filepath.Join(getProjectRoot(), "app", "models", snakeName+".go")
+	filePath := filepath.Join(getProjectRoot(), "app", "models", ctx.Snake+".go")
 
-	if err := writeFile(filePath, content); err != nil {
+	if err := writeFile("Model", filePath, content); err != nil {
 		printError(err)
 		return
 	}
-
-	printSuccess("Model", filePath)
-	fmt.Println("  → Don't forget to add this model to database/migrations/migrate.go")
 }
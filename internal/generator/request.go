@@ -1,34 +1,25 @@
 package generator
 
 import (
-	"fmt"
 	"path/filepath"
 )
 
-// MakeRequest generates a new request validation file
-func MakeRequest(name string) {
-	pascalName := toPascalCase(name)
-	snakeName := toSnakeCase(pascalName)
+// MakeRequest generates a new request validation file, with the given fields
+// or a single Name field
+func MakeRequest(name string, fields ...Field) {
+	ctx := newContext(name)
+	ctx.Fields = fields
 
-	content := fmt.Sprintf(`package requests
-
-type Create%sRequest struct {
-	Name string `+"`json:\"name\" validate:\"required,min=2,max=100\"`"+`
-	// Add your validation fields here
-}
-
-type Update%sRequest struct {
-	Name string `+"`json:\"name\" validate:\"required,min=2,max=100\"`"+`
-	// Add your validation fields here
-}
-`, pascalName, pascalName)
+	content, err := render("request", ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
 
-	filePath := filepath.Join(getProjectRoot(), "app", "requests", snakeName+"_request.go")
+	filePath := filepath.Join(getProjectRoot(), "app", "requests", ctx.Snake+"_request.go")
 
-	if err := writeFile(filePath, content); err != nil {
+	if err := writeFile("Request", filePath, content); err != nil {
 		printError(err)
 		return
 	}
-
-	printSuccess("Request", filePath)
 }
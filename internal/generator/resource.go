@@ -4,29 +4,70 @@ import (
 	"fmt"
 )
 
-// MakeResource generates a complete resource (model, controller, service, request)
-func MakeResource(name string) {
+// MakeResource generates a complete resource (model, controller, service,
+// request). With fields it also generates the create migration, a factory
+// and a seeder, so the resource works without hand edits. Unless wire is
+// false the routes and model are registered as well.
+func MakeResource(name string, wire bool, fields ...Field) {
 	pascalName := toPascalCase(name)
+	snakeName := toSnakeCase(pascalName)
 
 	fmt.Printf("\n🚀 Creating resource: %s\n\n", pascalName)
 
 	// Create Model
-	MakeModel(name)
+	MakeModel(name, fields...)
 
 	// Create Request
-	MakeRequest(name)
+	MakeRequest(name, fields...)
 
 	// Create Service
-	MakeService(name)
+	makeService(name, fields...)
 
 	// Create Controller
 	MakeController(name)
 
+	if len(fields) > 0 {
+		// Create Migration, Factory and Seeder
+		MakeMigration("create_"+toPlural(snakeName)+"_table", fields...)
+		MakeFactory(name)
+		MakeSeeder(name, fields...)
+	}
+
+	if wire {
+		fmt.Println()
+		WireResource(name)
+	}
+
+	switch {
+	case Failed():
+		fmt.Println("\n\033[31m✗\033[0m Resource created with errors, see above")
+		return
+	case options.DryRun:
+		fmt.Println("\n✨ Dry run finished, no files were written")
+		return
+	}
+
 	fmt.Println("\n✨ Resource created successfully!")
 	fmt.Println("\nNext steps:")
-	fmt.Printf("  1. Update the model fields in app/models/%s.go\n", toSnakeCase(pascalName))
-	fmt.Printf("  2. Update the request validation in app/requests/%s_request.go\n", toSnakeCase(pascalName))
-	fmt.Printf("  3. Update the service logic in app/services/%s_service.go\n", toSnakeCase(pascalName))
-	fmt.Printf("  4. Add the model to database/migrations/migrate.go\n")
-	fmt.Printf("  5. Register routes in routes/api.go\n")
+
+	var steps []string
+	if len(fields) == 0 {
+		steps = append(steps,
+			fmt.Sprintf("Update the model fields in app/models/%s.go", snakeName),
+			fmt.Sprintf("Update the request validation in app/requests/%s_request.go", snakeName),
+			fmt.Sprintf("Update the service logic in app/services/%s_service.go", snakeName))
+	}
+	if !wire {
+		steps = append(steps, "Register routes in routes/api.go")
+	}
+	if len(fields) > 0 {
+		steps = append(steps, "Run the migration: gomen migrate")
+	}
+
+	for i, step := range steps {
+		fmt.Printf("  %d. %s\n", i+1, step)
+	}
+	for _, field := range foreignFields(fields) {
+		fmt.Printf("  → %s references %s, make sure that table is migrated first\n", field.Column, field.References)
+	}
 }
@@ -1,55 +1,65 @@
 package generator
 
 import (
-	"fmt"
 	"path/filepath"
+	"strings"
 )
 
-// MakeSeeder generates a new seeder file
-func MakeSeeder(name string) {
-	pascalName := toPascalCase(name)
-	snakeName := toSnakeCase(pascalName)
-	camelName := toCamelCase(pascalName)
+// MakeSeeder generates a new seeder file. Foreign key fields are filled
+// with ids of existing rows of the referenced table.
+func MakeSeeder(name string, fields ...Field) {
+	ctx := newContext(strings.TrimSuffix(name, "Seeder"))
+	ctx.Fields = fields
 
-	content := fmt.Sprintf(`package seeders
+	root := getProjectRoot()
 
-import (
-	"gomen/app/models"
-	"gomen/config"
-	"log"
-)
-
-func Seed%s() {
-	db := config.GetDB()
-
-	%s := []models.%s{
-		{
-			Name: "Sample %s 1",
-			// Add more fields here
-		},
-		{
-			Name: "Sample %s 2",
-			// Add more fields here
-		},
-	}
-
-	for _, item := range %s {
-		if err := db.FirstOrCreate(&item, models.%s{Name: item.Name}).Error; err != nil {
-			log.Printf("Failed to seed %s: %%v", err)
+	// Prefer the model factory over hand-written records when there is one
+	stub := "seeder"
+	if fileExists(filepath.Join(root, "database", "factories", ctx.Snake+"_factory.go")) {
+		stub = "seeder.factory"
+		if len(fields) == 0 {
+			ctx.Fields = modelForeignKeys(root, ctx.Pascal)
 		}
 	}
 
-	log.Println("%s seeded successfully")
-}
-`, pascalName, camelName, pascalName, pascalName, pascalName, camelName, pascalName, snakeName, pascalName)
+	content, err := render(stub, ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
 
-	filePath := filepath.Join(getProjectRoot(), "database", "seeders", snakeName+"_seeder.go")
+	filePath := filepath.Join(root, "database", "seeders", ctx.Snake+"_seeder.go")
 
-	if err := writeFile(filePath, content); err != nil {
+	if err := writeFile("Seeder", filePath, content); err != nil {
 		printError(err)
 		return
 	}
+}
+
+func foreignFields(fields []Field) []Field {
+	var foreign []Field
+	for _, field := range fields {
+		if field.References != "" {
+			foreign = append(foreign, field)
+		}
+	}
+	return foreign
+}
 
-	printSuccess("Seeder", filePath)
-	fmt.Println("  → Don't forget to call this seeder in database/seeders/seeder.go")
+// modelForeignKeys guesses the foreign keys of a model from its uint *ID
+// fields: CategoryID references categories
+func modelForeignKeys(root, pascalName string) []Field {
+	fields, err := modelFields(filepath.Join(root, "app", "models", toSnakeCase(pascalName)+".go"), pascalName)
+	if err != nil {
+		return nil
+	}
+
+	var foreign []Field
+	for _, field := range fields {
+		if field.goType == "uint" && strings.HasSuffix(field.name, "ID") && field.name != "ID" {
+			column := toSnakeCase(strings.TrimSuffix(field.name, "ID"))
+			foreign = append(foreign, Field{Column: column + "_id", Type: "fk", References: toPlural(column)})
+		}
+	}
+	return foreign
 }
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// StubsDir is the project directory stub:publish copies the stubs to. A stub
+// found there is used instead of the embedded one.
+const StubsDir = "stubs"
+
+//go:embed stubs/*.stub
+var stubFiles embed.FS
+
+// Context is the data the stubs are rendered with. For make:resource
+// order_item the names are:
+//
+//	{{.Name}}         order_item, as given on the command line
+//	{{.Pascal}}       OrderItem
+//	{{.Snake}}        order_item
+//	{{.Camel}}        orderItem
+//	{{.Plural}}       OrderItems
+//	{{.PluralSnake}}  order_items
+//	{{.PluralCamel}}  orderItems
+//	{{.Table}}        order_items, the table of a model or migration
+//	{{.Module}}       the module path from go.mod, e.g. github.com/acme/myapi
+//
+// Stubs can also call the functions snake, pascal, camel and plural, e.g.
+// {{plural .Snake}}.
+type Context struct {
+	Name        string
+	Pascal      string
+	Snake       string
+	Camel       string
+	Plural      string
+	PluralSnake string
+	PluralCamel string
+	Table       string
+	Module      string
+
+	// Fields are the fields given to make:resource. Seeders generated on
+	// their own get the foreign keys read from the model.
+	Fields []Field
+	// Migration is the registered name of a migration, with its timestamp
+	Migration string
+	// Values are the fake values of a factory, one per model field
+	Values []FactoryValue
+	// Command is the name a console command runs with, e.g. users:prune
+	Command string
+}
+
+// FactoryValue is a model field and the Faker call that fills it. Value is
+// "" when the field cannot be faked, such as a foreign key.
+type FactoryValue struct {
+	Field string
+	Value string
+}
+
+// stubFuncs are the functions available in stubs
+var stubFuncs = template.FuncMap{
+	"snake":  toSnakeCase,
+	"pascal": toPascalCase,
+	"camel":  toCamelCase,
+	"plural": toPlural,
+}
+
+// newContext derives the template names from name
+func newContext(name string) Context {
+	pascalName := toPascalCase(name)
+	pluralName := toPlural(pascalName)
+
+	return Context{
+		Name:        name,
+		Pascal:      pascalName,
+		Snake:       toSnakeCase(pascalName),
+		Camel:       toCamelCase(pascalName),
+		Plural:      pluralName,
+		PluralSnake: toSnakeCase(pluralName),
+		PluralCamel: toCamelCase(pluralName),
+		Table:       toPlural(toSnakeCase(pascalName)),
+		Module:      modulePath(),
+	}
+}
+
+// UsesTime reports whether any of the fields needs the time package
+func (c Context) UsesTime() bool {
+	return usesTime(c.Fields)
+}
+
+// ForeignKeys returns the fk fields
+func (c Context) ForeignKeys() []Field {
+	return foreignFields(c.Fields)
+}
+
+// render executes the named stub, e.g. "model", with ctx and formats the
+// result as Go source
+func render(name string, ctx Context) (string, error) {
+	file := name + ".stub"
+
+	source, err := os.ReadFile(filepath.Join(getProjectRoot(), StubsDir, file))
+	if os.IsNotExist(err) {
+		source, err = stubFiles.ReadFile("stubs/" + file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read stub %s: %w", file, err)
+	}
+
+	tmpl, err := template.New(file).Funcs(stubFuncs).Parse(string(source))
+	if err != nil {
+		return "", fmt.Errorf("invalid stub: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, ctx); err != nil {
+		return "", fmt.Errorf("failed to render stub: %w", err)
+	}
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("stub %s produced invalid Go code: %w", file, err)
+	}
+	return string(formatted), nil
+}
+
+// PublishStubs copies the embedded stubs to the project's stubs directory so
+// they can be customized. Existing stubs are only replaced when force is set.
+func PublishStubs(force bool) {
+	dir := filepath.Join(getProjectRoot(), StubsDir)
+
+	entries, err := fs.ReadDir(stubFiles, "stubs")
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		printError(fmt.Errorf("failed to create directory: %w", err))
+		return
+	}
+
+	for _, entry := range entries {
+		filePath := filepath.Join(dir, entry.Name())
+
+		if _, err := os.Stat(filePath); err == nil && !force {
+			fmt.Printf("\033[33m•\033[0m Stub already published, kept: %s\n", filePath)
+			continue
+		}
+
+		content, err := stubFiles.ReadFile("stubs/" + entry.Name())
+		if err != nil {
+			printError(err)
+			continue
+		}
+		if err := os.WriteFile(filePath, content, 0644); err != nil {
+			printError(fmt.Errorf("failed to write file: %w", err))
+			continue
+		}
+
+		printSuccess("Stub", filePath)
+	}
+
+	fmt.Printf("\nEdit the stubs in %s/, the make:* commands use them instead of the built-in ones.\n", StubsDir)
+	fmt.Println("Run 'gomen stub:publish --force' to reset them to the built-in versions.")
+}
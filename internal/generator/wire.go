@@ -0,0 +1,387 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WireResource registers a generated resource: its routes in routes/api.go
+// and its model in the AutoMigrate registry. Running it again changes nothing.
+func WireResource(name string) {
+	pascalName := toPascalCase(name)
+	root := getProjectRoot()
+
+	for _, wire := range []func(root, pascalName string) (string, []byte, []byte, error){wireRoutes, wireModel} {
+		path, before, after, err := wire(root, pascalName)
+		if err != nil {
+			printError(err)
+			continue
+		}
+		if err := applyWiring(root, path, before, after, false); err != nil {
+			printError(err)
+		}
+	}
+}
+
+// applyWiring formats the edited source, writes it to path and prints the
+// diff. unwire only changes the messages.
+func applyWiring(root, path string, before, after []byte, unwire bool) error {
+	action, unchanged := "Wired", "Already wired"
+	if unwire {
+		action, unchanged = "Unwired", "Not wired"
+	}
+
+	relative, _ := filepath.Rel(root, path)
+	if string(before) == string(after) {
+		fmt.Printf("\033[33m•\033[0m %s: %s\n", unchanged, relative)
+		return nil
+	}
+
+	formatted, err := format.Source(after)
+	if err != nil {
+		return fmt.Errorf("editing %s produced invalid code, left it unchanged: %w", relative, err)
+	}
+
+	diff := unifiedDiff(filepath.ToSlash(relative), string(before), string(formatted))
+
+	if options.DryRun {
+		pending[path] = formatted
+		fmt.Printf("\033[36m•\033[0m Would be %s: %s\n", strings.ToLower(action), relative)
+		printDiff(diff)
+		return nil
+	}
+
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("\033[32m✓\033[0m %s: %s\n", action, relative)
+	printDiff(diff)
+	return nil
+}
+
+// insertion is text inserted at a byte offset of a source file
+type insertion struct {
+	offset int
+	text   string
+}
+
+// insertText applies insertions to src, keeping comments and layout intact
+func insertText(src []byte, insertions []insertion) []byte {
+	sort.Slice(insertions, func(i, j int) bool { return insertions[i].offset > insertions[j].offset })
+
+	out := append([]byte{}, src...)
+	for _, ins := range insertions {
+		out = append(out[:ins.offset], append([]byte(ins.text), out[ins.offset:]...)...)
+	}
+	return out
+}
+
+// wireRoutes adds a setupXRoutes function to routes/api.go and calls it from
+// SetupRoutes next to the other setup*Routes calls
+func wireRoutes(root, pascalName string) (string, []byte, []byte, error) {
+	path := filepath.Join(root, "routes", "api.go")
+	src, err := readFile(path)
+	if err != nil {
+		return path, nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return path, nil, nil, err
+	}
+
+	funcName := "setup" + pascalName + "Routes"
+	var insertions []insertion
+
+	if findFunc(file, funcName) == nil {
+		text, err := render("routes", newContext(pascalName))
+		if err != nil {
+			return path, nil, nil, err
+		}
+		insertions = append(insertions, insertion{offset: len(src), text: "\n" + text})
+	}
+
+	if !callsFunc(file, "SetupRoutes", funcName) {
+		setup := findFunc(file, "SetupRoutes")
+		if setup == nil {
+			return path, nil, nil, fmt.Errorf("SetupRoutes not found in %s", path)
+		}
+
+		last := lastSetupCall(setup)
+		if last == nil {
+			return path, nil, nil, fmt.Errorf("no setup*Routes call found in SetupRoutes, call %s(v1) by hand", funcName)
+		}
+
+		group := last.Args[0].(*ast.Ident).Name
+		insertions = append(insertions, insertion{
+			offset: fset.Position(last.End()).Offset,
+			text:   fmt.Sprintf("\n%s(%s)", funcName, group),
+		})
+	}
+
+	insertions = append(insertions, missingImports(fset, file, modulePath()+"/app/controllers", modulePath()+"/app/middlewares")...)
+
+	return path, src, insertText(src, insertions), nil
+}
+
+// unwireRoutes removes the setupXRoutes function and its call from
+// routes/api.go, along with the imports nothing else uses
+func unwireRoutes(root, pascalName string) (string, []byte, []byte, error) {
+	path := filepath.Join(root, "routes", "api.go")
+	src, err := readFile(path)
+	if err != nil {
+		return path, nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return path, nil, nil, err
+	}
+
+	funcName := "setup" + pascalName + "Routes"
+	var removals []removal
+
+	if fn := findFunc(file, funcName); fn != nil {
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		removals = append(removals, lineRange(src, fset.Position(start).Offset, fset.Position(fn.End()).Offset))
+	}
+
+	if setup := findFunc(file, "SetupRoutes"); setup != nil {
+		ast.Inspect(setup.Body, func(node ast.Node) bool {
+			stmt, ok := node.(*ast.ExprStmt)
+			if !ok {
+				return true
+			}
+			if call, ok := stmt.X.(*ast.CallExpr); ok {
+				if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == funcName {
+					removals = append(removals, lineRange(src, fset.Position(stmt.Pos()).Offset, fset.Position(stmt.End()).Offset))
+				}
+			}
+			return false
+		})
+	}
+
+	if len(removals) == 0 {
+		return path, src, src, nil
+	}
+
+	after, err := dropUnusedImports(path, removeText(src, removals), modulePath()+"/app/controllers", modulePath()+"/app/middlewares")
+	if err != nil {
+		return path, nil, nil, err
+	}
+	return path, src, after, nil
+}
+
+// removal is a byte range removed from a source file
+type removal struct {
+	start, end int
+}
+
+// lineRange widens start:end to the whole lines it covers
+func lineRange(src []byte, start, end int) removal {
+	for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+		start--
+	}
+	for end < len(src) && src[end] != '\n' {
+		end++
+	}
+	if end < len(src) {
+		end++
+	}
+	return removal{start: start, end: end}
+}
+
+// removeText applies removals to src
+func removeText(src []byte, removals []removal) []byte {
+	sort.Slice(removals, func(i, j int) bool { return removals[i].start > removals[j].start })
+
+	out := append([]byte{}, src...)
+	for _, r := range removals {
+		out = append(out[:r.start], out[r.end:]...)
+	}
+	return out
+}
+
+// dropUnusedImports removes those of paths the file no longer refers to
+func dropUnusedImports(path string, src []byte, paths ...string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var removals []removal
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		for _, p := range paths {
+			if importPath == p && !used[name] {
+				removals = append(removals, lineRange(src, fset.Position(spec.Pos()).Offset, fset.Position(spec.End()).Offset))
+			}
+		}
+	}
+
+	return removeText(src, removals), nil
+}
+
+// wireModel makes sure the model registers itself for AutoMigrate with
+// Register(&X{}) in an init function
+func wireModel(root, pascalName string) (string, []byte, []byte, error) {
+	path := filepath.Join(root, "app", "models", toSnakeCase(pascalName)+".go")
+	src, err := readFile(path)
+	if err != nil {
+		return path, nil, nil, fmt.Errorf("model not found: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return path, nil, nil, err
+	}
+
+	if registers(file, pascalName) {
+		return path, src, src, nil
+	}
+
+	register := fmt.Sprintf("Register(&%s{})", pascalName)
+
+	var ins insertion
+	if init := findFunc(file, "init"); init != nil {
+		ins = insertion{offset: fset.Position(init.Body.Rbrace).Offset, text: "\t" + register + "\n"}
+	} else {
+		ins = insertion{
+			offset: fset.Position(file.Decls[len(file.Decls)-1].End()).Offset,
+			text:   fmt.Sprintf("\n\nfunc init() {\n\t%s\n}", register),
+		}
+		for _, decl := range file.Decls {
+			// Before the model struct, as make:model writes it
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+				ins = insertion{offset: fset.Position(gen.Pos()).Offset, text: fmt.Sprintf("func init() {\n\t%s\n}\n\n", register)}
+				break
+			}
+		}
+	}
+
+	return path, src, insertText(src, []insertion{ins}), nil
+}
+
+// registers reports whether file calls Register(&name{})
+func registers(file *ast.File, name string) bool {
+	found := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return !found
+		}
+		if ident, ok := call.Fun.(*ast.Ident); !ok || ident.Name != "Register" {
+			return true
+		}
+		if unary, ok := call.Args[0].(*ast.UnaryExpr); ok {
+			if lit, ok := unary.X.(*ast.CompositeLit); ok {
+				if ident, ok := lit.Type.(*ast.Ident); ok && ident.Name == name {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// setupCalls returns the setup*Routes(group) calls in fn
+func setupCalls(fn *ast.FuncDecl) []*ast.CallExpr {
+	var calls []*ast.CallExpr
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok || !strings.HasPrefix(ident.Name, "setup") || !strings.HasSuffix(ident.Name, "Routes") {
+			return true
+		}
+		if _, ok := call.Args[0].(*ast.Ident); ok {
+			calls = append(calls, call)
+		}
+		return true
+	})
+	return calls
+}
+
+func lastSetupCall(fn *ast.FuncDecl) *ast.CallExpr {
+	calls := setupCalls(fn)
+	if len(calls) == 0 {
+		return nil
+	}
+	return calls[len(calls)-1]
+}
+
+// callsFunc reports whether the function caller calls callee
+func callsFunc(file *ast.File, caller, callee string) bool {
+	fn := findFunc(file, caller)
+	if fn == nil {
+		return false
+	}
+	for _, call := range setupCalls(fn) {
+		if call.Fun.(*ast.Ident).Name == callee {
+			return true
+		}
+	}
+	return false
+}
+
+// missingImports returns insertions adding the paths file does not import yet
+func missingImports(fset *token.FileSet, file *ast.File, paths ...string) []insertion {
+	imported := map[string]bool{}
+	for _, spec := range file.Imports {
+		imported[strings.Trim(spec.Path.Value, `"`)] = true
+	}
+
+	var insertions []insertion
+	for _, path := range paths {
+		if imported[path] || len(file.Imports) == 0 {
+			continue
+		}
+		insertions = append(insertions, insertion{
+			offset: fset.Position(file.Imports[0].Pos()).Offset,
+			text:   fmt.Sprintf("%q\n\t", path),
+		})
+	}
+	return insertions
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import "strings"
+
+// ExampleEmail is the example value of the email format
+const ExampleEmail = "user@example.com"
+
+// formatExamples are the example values of the string formats set by applyRules
+var formatExamples = map[string]string{
+	"email":     ExampleEmail,
+	"uri":       "https://example.com",
+	"uuid":      "123e4567-e89b-12d3-a456-426614174000",
+	"ipv4":      "127.0.0.1",
+	"ipv6":      "::1",
+	"ip":        "127.0.0.1",
+	"hostname":  "example.com",
+	"date-time": "2024-01-01T00:00:00Z",
+}
+
+// Operations returns the operations of the document by operation ID, e.g.
+// "ProductController.Store"
+func (d *Document) Operations() map[string]*Operation {
+	operations := map[string]*Operation{}
+	for _, item := range d.Paths {
+		for _, op := range item {
+			if op.OperationID != "" {
+				operations[op.OperationID] = op
+			}
+		}
+	}
+	return operations
+}
+
+// Example returns a value that satisfies schema, for request bodies in
+// exported collections. Strings are derived from the property names and
+// respect the format and length constraints, numbers respect their bounds.
+func (d *Document) Example(schema *Schema) interface{} {
+	return d.example("", schema, map[string]bool{})
+}
+
+func (d *Document) example(name string, schema *Schema, seen map[string]bool) interface{} {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		ref := strings.TrimPrefix(schema.Ref, refPrefix)
+		// Stop at recursive references
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+		return d.example(name, d.Components.Schemas[ref], seen)
+	}
+
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		object := map[string]interface{}{}
+		for _, part := range schema.AllOf {
+			if values, ok := d.example(name, part, seen).(map[string]interface{}); ok {
+				for key, value := range values {
+					object[key] = value
+				}
+			}
+		}
+		return object
+	}
+
+	switch schema.Type {
+	case "object":
+		object := map[string]interface{}{}
+		for property, propertySchema := range schema.Properties {
+			object[property] = d.example(property, propertySchema, seen)
+		}
+		return object
+	case "array":
+		items := []interface{}{}
+		count := 1
+		if schema.MinItems != nil && *schema.MinItems > count {
+			count = *schema.MinItems
+		}
+		for i := 0; i < count; i++ {
+			items = append(items, d.example(name, schema.Items, seen))
+		}
+		return items
+	case "integer":
+		return int64(bounded(schema, 1))
+	case "number":
+		return bounded(schema, 1)
+	case "boolean":
+		return true
+	case "string":
+		return exampleString(name, schema)
+	}
+
+	return nil
+}
+
+// bounded moves value into the minimum and maximum of schema
+func bounded(schema *Schema, value float64) float64 {
+	if min := schema.Minimum; min != nil && (value < *min || schema.ExclusiveMinimum && value <= *min) {
+		value = *min
+		if schema.ExclusiveMinimum {
+			value++
+		}
+	}
+	if max := schema.Maximum; max != nil && (value > *max || schema.ExclusiveMaximum && value >= *max) {
+		value = *max
+		if schema.ExclusiveMaximum {
+			value--
+		}
+	}
+	return value
+}
+
+// exampleString derives a string from the property name, e.g. "Example name"
+// for name, padded or cut to the length constraints
+func exampleString(name string, schema *Schema) string {
+	if example, ok := formatExamples[schema.Format]; ok {
+		return example
+	}
+	if strings.Contains(name, "password") {
+		return "password123"
+	}
+
+	value := "Example " + strings.ReplaceAll(name, "_", " ")
+	if name == "" {
+		value = "string"
+	}
+
+	switch schema.Pattern {
+	case "^[a-zA-Z]+$", "^[a-zA-Z0-9]+$":
+		value = strings.ReplaceAll(value, " ", "")
+	case "^[-+]?[0-9]+(?:\\.[0-9]+)?$":
+		value = "123"
+	case "^[^A-Z]*$":
+		value = strings.ToLower(value)
+	case "^[^a-z]*$":
+		value = strings.ToUpper(value)
+	}
+
+	if schema.MinLength != nil && len(value) < *schema.MinLength {
+		value += strings.Repeat("x", *schema.MinLength-len(value))
+	}
+	if schema.MaxLength != nil && len(value) > *schema.MaxLength {
+		value = value[:*schema.MaxLength]
+	}
+	return value
+}
@@ -0,0 +1,441 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// @Param name in type required "description" attributes
+	paramPattern = regexp.MustCompile(`^(\S+)\s+(\w+)\s+(\S+)\s+(true|false)(?:\s+"([^"]*)")?(.*)$`)
+	// @Success code {kind} type "description"
+	responsePattern = regexp.MustCompile(`^(\d{3}|default)\s+\{(\w+)\}\s+(\S+)(?:\s+"([^"]*)")?`)
+	// @Router /path [method]
+	routerPattern = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+	// default(1), minimum(0), maximum(100), enums(a,b)
+	attributePattern = regexp.MustCompile(`(\w+)\(([^)]*)\)`)
+	// {id} in a path
+	pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// mimeTypes are the short names swag accepts in @Accept and @Produce
+var mimeTypes = map[string]string{
+	"json":                  "application/json",
+	"xml":                   "application/xml",
+	"plain":                 "text/plain",
+	"html":                  "text/html",
+	"mpfd":                  "multipart/form-data",
+	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
+	"octet-stream":          "application/octet-stream",
+}
+
+// Generate builds the document of the project in root: the general
+// information from main.go and an operation for each handler under app/
+// annotated with @Router
+func Generate(root string) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: filepath.Base(root), Version: "1.0.0"},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			SecuritySchemes: map[string]SecurityScheme{},
+		},
+	}
+
+	if err := parseGeneralInfo(doc, filepath.Join(root, "main.go")); err != nil {
+		return nil, err
+	}
+
+	files, err := parseDir(filepath.Join(root, "app"))
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{doc: doc, types: indexTypes(files)}
+
+	for _, file := range files.files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			if err := g.operation(fn); err != nil {
+				g.errors = append(g.errors, fmt.Sprintf("%s: %s", files.fset.Position(fn.Pos()), err))
+			}
+		}
+	}
+
+	if len(g.errors) > 0 {
+		return nil, fmt.Errorf("invalid annotations:\n  %s", strings.Join(g.errors, "\n  "))
+	}
+
+	doc.collectTags()
+
+	return doc, nil
+}
+
+// annotations returns the @ annotations of a comment group as name, value pairs
+func annotations(group *ast.CommentGroup) [][2]string {
+	if group == nil {
+		return nil
+	}
+
+	var pairs [][2]string
+	for _, comment := range group.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(text, "@") {
+			continue
+		}
+		name, value := text, ""
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			name, value = text[:i], strings.TrimSpace(text[i+1:])
+		}
+		pairs = append(pairs, [2]string{strings.ToLower(name), value})
+	}
+	return pairs
+}
+
+// parseGeneralInfo reads @title, @version, @description, @BasePath and the
+// security definitions from the comments of main.go. Like swag, @in, @name
+// and @description after a security definition belong to it.
+func parseGeneralInfo(doc *Document, path string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var current string
+	for _, group := range file.Comments {
+		for _, pair := range annotations(group) {
+			name, value := pair[0], pair[1]
+			switch {
+			case name == "@title":
+				doc.Info.Title = value
+			case name == "@version":
+				doc.Info.Version = value
+			case name == "@description" && current == "":
+				doc.Info.Description = strings.TrimSpace(doc.Info.Description + " " + value)
+			case name == "@basepath":
+				doc.Servers = []Server{{URL: value}}
+			case name == "@securitydefinitions.apikey":
+				current = value
+				doc.Components.SecuritySchemes[current] = SecurityScheme{Type: "apiKey"}
+			case name == "@securitydefinitions.basic":
+				current = value
+				doc.Components.SecuritySchemes[current] = SecurityScheme{Type: "http", Scheme: "basic"}
+			case current != "" && (name == "@in" || name == "@name" || name == "@description"):
+				scheme := doc.Components.SecuritySchemes[current]
+				switch name {
+				case "@in":
+					scheme.In = value
+				case "@name":
+					scheme.Name = value
+				default:
+					scheme.Description = value
+				}
+				doc.Components.SecuritySchemes[current] = scheme
+			}
+		}
+	}
+	return nil
+}
+
+// parsedFiles are the Go files of a directory tree
+type parsedFiles struct {
+	fset  *token.FileSet
+	files []*ast.File
+}
+
+func parseDir(dir string) (*parsedFiles, error) {
+	parsed := &parsedFiles{fset: token.NewFileSet()}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(parsed.fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		parsed.files = append(parsed.files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// generator fills a document from the parsed sources
+type generator struct {
+	doc    *Document
+	types  map[string]*typeDecl
+	errors []string
+	// building guards against recursive types
+	building map[string]bool
+}
+
+// operation adds the operation documented by the annotations of fn
+func (g *generator) operation(fn *ast.FuncDecl) error {
+	pairs := annotations(fn.Doc)
+
+	var path, method string
+	for _, pair := range pairs {
+		if pair[0] == "@router" {
+			matches := routerPattern.FindStringSubmatch(pair[1])
+			if matches == nil {
+				return fmt.Errorf("invalid @Router %q, expected /path [method]", pair[1])
+			}
+			path, method = matches[1], strings.ToLower(matches[2])
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	op := &Operation{
+		OperationID: operationID(fn),
+		Responses:   map[string]Response{},
+	}
+	accept, produce := []string{"application/json"}, []string{"application/json"}
+
+	for _, pair := range pairs {
+		name, value := pair[0], pair[1]
+		switch name {
+		case "@summary":
+			op.Summary = value
+		case "@description":
+			op.Description = strings.TrimSpace(op.Description + "\n" + value)
+		case "@id":
+			op.OperationID = value
+		case "@tags":
+			for _, tag := range strings.Split(value, ",") {
+				op.Tags = append(op.Tags, strings.TrimSpace(tag))
+			}
+		case "@accept":
+			accept = mimeList(value)
+		case "@produce":
+			produce = mimeList(value)
+		case "@security":
+			for _, scheme := range strings.Split(value, "||") {
+				op.Security = append(op.Security, map[string][]string{strings.TrimSpace(scheme): {}})
+			}
+		case "@deprecated":
+			op.Deprecated = true
+		}
+	}
+
+	for _, pair := range pairs {
+		var err error
+		switch pair[0] {
+		case "@param":
+			err = g.param(op, pair[1], accept)
+		case "@success", "@failure", "@response":
+			err = g.response(op, pair[1], produce)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Declare path parameters the annotations left out
+	for _, matches := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		if !hasParam(op, matches[1], "path") {
+			op.Parameters = append(op.Parameters, Parameter{Name: matches[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = Response{Description: http.StatusText(http.StatusOK)}
+	}
+
+	item := g.doc.Paths[path]
+	if item == nil {
+		item = PathItem{}
+		g.doc.Paths[path] = item
+	}
+	if _, exists := item[method]; exists {
+		return fmt.Errorf("%s %s is documented twice", strings.ToUpper(method), path)
+	}
+	item[method] = op
+	return nil
+}
+
+// operationID names an operation after its handler, e.g. ProductController.Index
+func operationID(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+func mimeList(value string) []string {
+	var list []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if mime, ok := mimeTypes[name]; ok {
+			name = mime
+		}
+		list = append(list, name)
+	}
+	return list
+}
+
+func hasParam(op *Operation, name, in string) bool {
+	for _, param := range op.Parameters {
+		if param.Name == name && param.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// param adds a @Param: a request body or a path, query or header parameter
+func (g *generator) param(op *Operation, value string, accept []string) error {
+	matches := paramPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return fmt.Errorf("invalid @Param %q, expected name in type required \"description\"", value)
+	}
+	name, in, typ, required, description, attributes := matches[1], matches[2], matches[3], matches[4] == "true", matches[5], matches[6]
+
+	schema, err := g.typeSchema(typ)
+	if err != nil {
+		return err
+	}
+
+	if in == "body" {
+		op.RequestBody = &RequestBody{Description: description, Required: required, Content: map[string]MediaType{}}
+		for _, mime := range accept {
+			op.RequestBody.Content[mime] = MediaType{Schema: schema}
+		}
+		return nil
+	}
+
+	switch in {
+	case "path":
+		required = true
+	case "query", "header", "cookie":
+	case "formData":
+		return fmt.Errorf("formData parameters are not supported, document a body struct instead")
+	default:
+		return fmt.Errorf("invalid @Param location %q for %s", in, name)
+	}
+
+	for _, attribute := range attributePattern.FindAllStringSubmatch(attributes, -1) {
+		switch strings.ToLower(attribute[1]) {
+		case "default":
+			schema.Default = literal(schema.Type, attribute[2])
+		case "minimum":
+			schema.Minimum = number(attribute[2])
+		case "maximum":
+			schema.Maximum = number(attribute[2])
+		case "minlength":
+			schema.MinLength = integer(attribute[2])
+		case "maxlength":
+			schema.MaxLength = integer(attribute[2])
+		case "enums":
+			for _, enum := range strings.Split(attribute[2], ",") {
+				schema.Enum = append(schema.Enum, literal(schema.Type, strings.TrimSpace(enum)))
+			}
+		}
+	}
+
+	op.Parameters = append(op.Parameters, Parameter{Name: name, In: in, Description: description, Required: required, Schema: schema})
+	return nil
+}
+
+// response adds a @Success, @Failure or @Response
+func (g *generator) response(op *Operation, value string, produce []string) error {
+	matches := responsePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return fmt.Errorf("invalid response %q, expected code {object} type \"description\"", value)
+	}
+	code, kind, typ, description := matches[1], matches[2], matches[3], matches[4]
+
+	if description == "" {
+		status, _ := strconv.Atoi(code)
+		description = http.StatusText(status)
+		if description == "" {
+			description = "Response"
+		}
+	}
+
+	response := Response{Description: description}
+	if typ != "nil" {
+		schema, err := g.typeSchema(typ)
+		if err != nil {
+			return err
+		}
+		if kind == "array" {
+			schema = &Schema{Type: "array", Items: schema}
+		}
+
+		response.Content = map[string]MediaType{}
+		for _, mime := range produce {
+			response.Content[mime] = MediaType{Schema: schema}
+		}
+	}
+
+	op.Responses[code] = response
+	return nil
+}
+
+// literal converts an annotation value to the JSON type of the schema
+func literal(typ, value string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func number(value string) *float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func integer(value string) *int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
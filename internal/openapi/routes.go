@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Route is a registered route, as printed by route:list --json
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// pathParam matches the parameters of OpenAPI ({id}) and gin (:id, *file) paths
+var pathParam = regexp.MustCompile(`\{[^/}]+\}|:[^/]+|\*[^/]+`)
+
+// Prune removes the operations whose @Router matches no registered route,
+// so the document does not advertise endpoints that answer 404. Paths are
+// relative to the server URL from @BasePath. It returns the removed
+// operations, e.g. "GET /abouts/{id}".
+func (d *Document) Prune(routes []Route) []string {
+	base := ""
+	if len(d.Servers) > 0 && strings.HasPrefix(d.Servers[0].URL, "/") {
+		base = strings.TrimSuffix(d.Servers[0].URL, "/")
+	}
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[routeKey(route.Method, route.Path)] = true
+	}
+
+	var pruned []string
+	for path, item := range d.Paths {
+		for method := range item {
+			if !registered[routeKey(method, base+path)] {
+				pruned = append(pruned, strings.ToUpper(method)+" "+path)
+				delete(item, method)
+			}
+		}
+		if len(item) == 0 {
+			delete(d.Paths, path)
+		}
+	}
+	sort.Strings(pruned)
+
+	if len(pruned) > 0 {
+		d.collectTags()
+		d.pruneSchemas()
+	}
+	return pruned
+}
+
+// routeKey identifies a route by method and path with unnamed parameters,
+// so /products/{id} matches /products/:id
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + pathParam.ReplaceAllString(path, "{}")
+}
+
+// pruneSchemas removes the component schemas no operation refers to,
+// directly or through another schema
+func (d *Document) pruneSchemas() {
+	used := map[string]bool{}
+	var mark func(schema *Schema)
+	mark = func(schema *Schema) {
+		if schema == nil {
+			return
+		}
+		if name := strings.TrimPrefix(schema.Ref, refPrefix); schema.Ref != "" && !used[name] {
+			used[name] = true
+			mark(d.Components.Schemas[name])
+		}
+		for _, property := range schema.Properties {
+			mark(property)
+		}
+		for _, part := range schema.AllOf {
+			mark(part)
+		}
+		mark(schema.Items)
+		mark(schema.AdditionalProperties)
+	}
+
+	for _, item := range d.Paths {
+		for _, op := range item {
+			for _, param := range op.Parameters {
+				mark(param.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					mark(media.Schema)
+				}
+			}
+			for _, response := range op.Responses {
+				for _, media := range response.Content {
+					mark(media.Schema)
+				}
+			}
+		}
+	}
+
+	for name := range d.Components.Schemas {
+		if !used[name] {
+			delete(d.Components.Schemas, name)
+		}
+	}
+}
+
+// collectTags lists the tags used by the operations of the document
+func (d *Document) collectTags() {
+	tags := map[string]bool{}
+	for _, item := range d.Paths {
+		for _, op := range item {
+			for _, tag := range op.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+
+	d.Tags = nil
+	for tag := range tags {
+		d.Tags = append(d.Tags, Tag{Name: tag})
+	}
+	sort.Slice(d.Tags, func(i, j int) bool { return d.Tags[i].Name < d.Tags[j].Name })
+}
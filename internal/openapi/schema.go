@@ -0,0 +1,450 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// typeDecl is a type declared in the parsed sources
+type typeDecl struct {
+	pkg  string
+	spec *ast.TypeSpec
+	doc  *ast.CommentGroup
+}
+
+// indexTypes maps "package.Type" to the types declared in files
+func indexTypes(files *parsedFiles) map[string]*typeDecl {
+	types := map[string]*typeDecl{}
+	for _, file := range files.files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					doc := typeSpec.Doc
+					if doc == nil {
+						doc = gen.Doc
+					}
+					types[file.Name.Name+"."+typeSpec.Name.Name] = &typeDecl{pkg: file.Name.Name, spec: typeSpec, doc: doc}
+				}
+			}
+		}
+	}
+	return types
+}
+
+// primitives are the type names accepted in annotations besides structs
+var primitives = map[string]Schema{
+	"string":  {Type: "string"},
+	"int":     {Type: "integer"},
+	"integer": {Type: "integer"},
+	"uint":    {Type: "integer", Minimum: new(float64)},
+	"number":  {Type: "number"},
+	"float64": {Type: "number", Format: "double"},
+	"float32": {Type: "number", Format: "float"},
+	"bool":    {Type: "boolean"},
+	"boolean": {Type: "boolean"},
+	"object":  {Type: "object"},
+	"file":    {Type: "string", Format: "binary"},
+}
+
+// typeSchema returns the schema of a type written in an annotation:
+// a primitive, []T, pkg.Struct or pkg.Struct{field=T,...}
+func (g *generator) typeSchema(typ string) (*Schema, error) {
+	if strings.HasPrefix(typ, "[]") {
+		items, err := g.typeSchema(typ[2:])
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	}
+
+	if primitive, ok := primitives[typ]; ok {
+		return &primitive, nil
+	}
+
+	// responses.Response{data=models.Product} overrides fields of a struct
+	if i := strings.Index(typ, "{"); i > 0 && strings.HasSuffix(typ, "}") {
+		base, err := g.typeSchema(typ[:i])
+		if err != nil {
+			return nil, err
+		}
+
+		overrides := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for _, field := range splitFields(typ[i+1 : len(typ)-1]) {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid field override %q in %s", field, typ)
+			}
+			schema, err := g.typeSchema(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			overrides.Properties[parts[0]] = schema
+		}
+		return &Schema{AllOf: []*Schema{base, overrides}}, nil
+	}
+
+	if _, ok := g.types[typ]; !ok {
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
+	return g.ref(typ), nil
+}
+
+// splitFields splits a=T,b=U on the commas outside of braces
+func splitFields(s string) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				fields = append(fields, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(fields, s[start:])
+}
+
+// ref returns a reference to the component schema of a declared type,
+// adding the schema to the document the first time
+func (g *generator) ref(name string) *Schema {
+	ref := &Schema{Ref: refPrefix + name}
+	if _, done := g.doc.Components.Schemas[name]; done || g.building[name] {
+		return ref
+	}
+
+	if g.building == nil {
+		g.building = map[string]bool{}
+	}
+	g.building[name] = true
+	defer delete(g.building, name)
+
+	decl := g.types[name]
+	schema := g.exprSchema(decl.pkg, decl.spec.Type)
+	if decl.doc != nil {
+		schema.Description = strings.TrimSpace(decl.doc.Text())
+	}
+	g.doc.Components.Schemas[name] = schema
+	return ref
+}
+
+// exprSchema returns the schema of a Go type expression of package pkg
+func (g *generator) exprSchema(pkg string, expr ast.Expr) *Schema {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if primitive, ok := goPrimitive(e.Name); ok {
+			return primitive
+		}
+		if _, ok := g.types[pkg+"."+e.Name]; ok {
+			return g.ref(pkg + "." + e.Name)
+		}
+		return &Schema{}
+
+	case *ast.SelectorExpr:
+		name := exprName(e)
+		switch name {
+		case "time.Time":
+			return &Schema{Type: "string", Format: "date-time"}
+		case "time.Duration":
+			return &Schema{Type: "integer"}
+		case "gorm.DeletedAt", "sql.NullTime":
+			return &Schema{Type: "string", Format: "date-time", Nullable: true}
+		}
+		if _, ok := g.types[name]; ok {
+			return g.ref(name)
+		}
+		return &Schema{}
+
+	case *ast.StarExpr:
+		schema := g.exprSchema(pkg, e.X)
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+
+	case *ast.ArrayType:
+		if ident, ok := e.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.exprSchema(pkg, e.Elt)}
+
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: g.exprSchema(pkg, e.Value)}
+
+	case *ast.StructType:
+		return g.structSchema(pkg, e)
+	}
+
+	// interface{} and anything else can hold any value
+	return &Schema{}
+}
+
+func exprName(sel *ast.SelectorExpr) string {
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		return ident.Name + "." + sel.Sel.Name
+	}
+	return sel.Sel.Name
+}
+
+func goPrimitive(name string) (*Schema, bool) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, true
+	case "bool":
+		return &Schema{Type: "boolean"}, true
+	case "int", "int8", "int16", "int32":
+		return &Schema{Type: "integer", Format: "int32"}, true
+	case "int64":
+		return &Schema{Type: "integer", Format: "int64"}, true
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return &Schema{Type: "integer", Minimum: new(float64)}, true
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}, true
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}, true
+	case "any":
+		return &Schema{}, true
+	}
+	return nil, false
+}
+
+// structSchema returns the object schema of a struct. Embedded structs are
+// flattened like encoding/json does and validate tags become constraints.
+func (g *generator) structSchema(pkg string, st *ast.StructType) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted)
+			}
+		}
+
+		jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			if embedded := g.embedded(pkg, field.Type); embedded != nil && jsonName == "" {
+				for name, property := range embedded.Properties {
+					schema.Properties[name] = property
+				}
+				schema.Required = append(schema.Required, embedded.Required...)
+				continue
+			}
+		}
+
+		for _, name := range fieldNames(field) {
+			if !ast.IsExported(name) {
+				continue
+			}
+			propertyName := jsonName
+			if propertyName == "" {
+				propertyName = name
+			}
+
+			property := g.exprSchema(pkg, field.Type)
+			if field.Doc != nil {
+				property = describe(property, strings.TrimSpace(field.Doc.Text()))
+			}
+
+			rules := tag.Get("validate")
+			if binding := tag.Get("binding"); binding != "" {
+				rules = strings.TrimPrefix(rules+","+binding, ",")
+			}
+			if applyRules(property, rules) {
+				schema.Required = append(schema.Required, propertyName)
+			}
+
+			schema.Properties[propertyName] = property
+		}
+	}
+
+	return schema
+}
+
+// embedded returns the object schema of an embedded struct type
+func (g *generator) embedded(pkg string, expr ast.Expr) *Schema {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	name := ""
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = pkg + "." + e.Name
+	case *ast.SelectorExpr:
+		name = exprName(e)
+	}
+
+	decl, ok := g.types[name]
+	if !ok {
+		return nil
+	}
+	st, ok := decl.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	return g.structSchema(decl.pkg, st)
+}
+
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		// An embedded non-struct type is a field named after the type
+		switch e := field.Type.(type) {
+		case *ast.Ident:
+			return []string{e.Name}
+		case *ast.StarExpr:
+			if ident, ok := e.X.(*ast.Ident); ok {
+				return []string{ident.Name}
+			}
+		}
+		return nil
+	}
+
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return names
+}
+
+// describe sets the description of a property. References cannot have
+// siblings, so they are wrapped in allOf.
+func describe(schema *Schema, description string) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Description: description}
+	}
+	schema.Description = description
+	return schema
+}
+
+// applyRules turns validator rules into constraints of schema and reports
+// whether the rules make the field required
+func applyRules(schema *Schema, rules string) bool {
+	required := false
+
+	parts := strings.Split(rules, ",")
+	for i, rule := range parts {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "required":
+			required = true
+			continue
+		case "dive":
+			// The rules after dive apply to the elements
+			if schema.Items != nil {
+				applyRules(schema.Items, strings.Join(parts[i+1:], ","))
+			}
+			return required
+		}
+
+		// References have no constraints of their own
+		if schema.Ref != "" {
+			continue
+		}
+
+		switch name {
+		case "min", "gte":
+			setLowerBound(schema, param, false)
+		case "max", "lte":
+			setUpperBound(schema, param, false)
+		case "gt":
+			setLowerBound(schema, param, true)
+		case "lt":
+			setUpperBound(schema, param, true)
+		case "len":
+			setLowerBound(schema, param, false)
+			setUpperBound(schema, param, false)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, literal(schema.Type, value))
+			}
+		case "email":
+			schema.Format = "email"
+		case "url", "uri", "http_url":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "ip", "ipv4", "ipv6":
+			schema.Format = name
+		case "hostname":
+			schema.Format = "hostname"
+		case "datetime":
+			schema.Format = "date-time"
+		case "alpha":
+			schema.Pattern = "^[a-zA-Z]+$"
+		case "alphanum":
+			schema.Pattern = "^[a-zA-Z0-9]+$"
+		case "numeric":
+			schema.Pattern = "^[-+]?[0-9]+(?:\\.[0-9]+)?$"
+		case "lowercase":
+			schema.Pattern = "^[^A-Z]*$"
+		case "uppercase":
+			schema.Pattern = "^[^a-z]*$"
+		}
+	}
+
+	return required
+}
+
+// setLowerBound applies min/gte/gt: a length for strings, a count for
+// arrays and a minimum for numbers
+func setLowerBound(schema *Schema, param string, exclusive bool) {
+	switch schema.Type {
+	case "string":
+		if n := integer(param); n != nil {
+			if exclusive {
+				*n++
+			}
+			schema.MinLength = n
+		}
+	case "array":
+		if n := integer(param); n != nil {
+			if exclusive {
+				*n++
+			}
+			schema.MinItems = n
+		}
+	case "integer", "number":
+		schema.Minimum = number(param)
+		schema.ExclusiveMinimum = exclusive && schema.Minimum != nil
+	}
+}
+
+// setUpperBound applies max/lte/lt like setLowerBound
+func setUpperBound(schema *Schema, param string, exclusive bool) {
+	switch schema.Type {
+	case "string":
+		if n := integer(param); n != nil {
+			if exclusive {
+				*n--
+			}
+			schema.MaxLength = n
+		}
+	case "array":
+		if n := integer(param); n != nil {
+			if exclusive {
+				*n--
+			}
+			schema.MaxItems = n
+		}
+	case "integer", "number":
+		schema.Maximum = number(param)
+		schema.ExclusiveMaximum = exclusive && schema.Maximum != nil
+	}
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestApplyRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		schema       Schema
+		rules        string
+		want         string
+		wantRequired bool
+	}{
+		{"no rules", Schema{Type: "string"}, "", `{"type":"string"}`, false},
+		{"required", Schema{Type: "string"}, "required", `{"type":"string"}`, true},
+		{"omitempty", Schema{Type: "string"}, "omitempty,max=10", `{"type":"string","maxLength":10}`, false},
+		{"string length", Schema{Type: "string"}, "required,min=3,max=100", `{"type":"string","minLength":3,"maxLength":100}`, true},
+		{"string exclusive length", Schema{Type: "string"}, "gt=2,lt=10", `{"type":"string","minLength":3,"maxLength":9}`, false},
+		{"string len", Schema{Type: "string"}, "len=6", `{"type":"string","minLength":6,"maxLength":6}`, false},
+		{"string bad length", Schema{Type: "string"}, "min=x", `{"type":"string"}`, false},
+		{"integer range", Schema{Type: "integer"}, "gte=1,lte=5", `{"type":"integer","minimum":1,"maximum":5}`, false},
+		{"number exclusive range", Schema{Type: "number"}, "gt=0,lt=0.5", `{"type":"number","minimum":0,"maximum":0.5,"exclusiveMinimum":true,"exclusiveMaximum":true}`, false},
+		{"number bad bound", Schema{Type: "number"}, "gt=x", `{"type":"number"}`, false},
+		{"array count", Schema{Type: "array", Items: &Schema{Type: "string"}}, "min=1,max=3", `{"type":"array","items":{"type":"string"},"minItems":1,"maxItems":3}`, false},
+		{"boolean ignores bounds", Schema{Type: "boolean"}, "min=1", `{"type":"boolean"}`, false},
+		{"string enum", Schema{Type: "string"}, "oneof=draft published", `{"type":"string","enum":["draft","published"]}`, false},
+		{"integer enum", Schema{Type: "integer"}, "oneof=1 2 3", `{"type":"integer","enum":[1,2,3]}`, false},
+		{"email", Schema{Type: "string"}, "required,email", `{"type":"string","format":"email"}`, true},
+		{"url", Schema{Type: "string"}, "http_url", `{"type":"string","format":"uri"}`, false},
+		{"uuid", Schema{Type: "string"}, "uuid4", `{"type":"string","format":"uuid"}`, false},
+		{"ip", Schema{Type: "string"}, "ipv6", `{"type":"string","format":"ipv6"}`, false},
+		{"datetime", Schema{Type: "string"}, "datetime=2006-01-02", `{"type":"string","format":"date-time"}`, false},
+		{"alphanum", Schema{Type: "string"}, "alphanum", `{"type":"string","pattern":"^[a-zA-Z0-9]+$"}`, false},
+		{"spaces around rules", Schema{Type: "string"}, " required , max=5 ", `{"type":"string","maxLength":5}`, true},
+		{"unknown rules", Schema{Type: "string"}, "excludesall=;,containsrune=x", `{"type":"string"}`, false},
+		{"reference", Schema{Ref: refPrefix + "Address"}, "required,min=1", `{"$ref":"#/components/schemas/Address"}`, true},
+		{
+			"dive",
+			Schema{Type: "array", Items: &Schema{Type: "string"}},
+			"required,max=5,dive,email,max=50",
+			`{"type":"array","items":{"type":"string","format":"email","maxLength":50},"maxItems":5}`,
+			true,
+		},
+		{
+			"required after dive belongs to the elements",
+			Schema{Type: "array", Items: &Schema{Type: "integer"}},
+			"dive,required,min=1",
+			`{"type":"array","items":{"type":"integer","minimum":1}}`,
+			false,
+		},
+		{
+			"dive inside a parameter",
+			Schema{Type: "array", Items: &Schema{Type: "string"}},
+			"min=1,dive,oneof=dive swim",
+			`{"type":"array","items":{"type":"string","enum":["dive","swim"]},"minItems":1}`,
+			false,
+		},
+		{"dive without items", Schema{Type: "string"}, "max=5,dive,min=1", `{"type":"string","maxLength":5}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := tt.schema
+			required := applyRules(&schema, tt.rules)
+
+			got, err := json.Marshal(schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyRules(%q) =\n%s\nwant\n%s", tt.rules, got, tt.want)
+			}
+			if required != tt.wantRequired {
+				t.Errorf("applyRules(%q) required = %v, want %v", tt.rules, required, tt.wantRequired)
+			}
+		})
+	}
+}
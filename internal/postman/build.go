@@ -0,0 +1,224 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"gomen/internal/openapi"
+	"gomen/routes"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TokenVariable holds the JWT sent by the requests behind AuthMiddleware
+const TokenVariable = "token"
+
+// tokenRoutes store the token of their response in TokenVariable
+var tokenRoutes = map[string]bool{"auth.login": true, "auth.refresh_token": true}
+
+// tokenScript is the test script of tokenRoutes
+var tokenScript = []string{
+	"const body = pm.response.json();",
+	"if (pm.response.code === 200 && body.data && body.data.token) {",
+	"    pm.environment.set(\"" + TokenVariable + "\", body.data.token);",
+	"    pm.collectionVariables.set(\"" + TokenVariable + "\", body.data.token);",
+	"}",
+}
+
+// firstRoutes are sent before the other requests, so a run of the
+// collection registers and logs in before calling the protected routes
+var firstRoutes = map[string]int{"auth.register": 1, "auth.login": 2}
+
+// versionPrefix is left out when routes are grouped into folders
+var versionPrefix = regexp.MustCompile(`^/api(/v\d+)?`)
+
+// Build creates a collection with a request per route, grouped into folders
+// by the tags of their OpenAPI operation or the first segment of their path.
+// Request bodies are examples of the request structs documented with
+// @Param body. Each request tests for the status of its @Success comment.
+// Requests behind AuthMiddleware inherit the bearer auth of the
+// collection, the others use none. Register and login come first.
+func Build(name, baseURL string, list []routes.Route, doc *openapi.Document) *Collection {
+	collection := &Collection{
+		Info: Info{Name: name, Schema: SchemaURL},
+		Auth: &Auth{
+			Type:   "bearer",
+			Bearer: []Variable{{Key: "token", Value: "{{" + TokenVariable + "}}", Type: "string"}},
+		},
+		Variable: []Variable{
+			{Key: "baseUrl", Value: baseURL, Type: "string"},
+			{Key: TokenVariable, Value: "", Type: "string"},
+		},
+	}
+
+	operations := map[string]*openapi.Operation{}
+	if doc != nil {
+		operations = doc.Operations()
+	}
+
+	ordered := append([]routes.Route(nil), list...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return routeRank(ordered[i]) < routeRank(ordered[j])
+	})
+
+	folders := map[string]*Item{}
+	for _, route := range ordered {
+		op := operations[route.Handler]
+
+		folderName := folderOf(route.Path, op)
+		folder, ok := folders[folderName]
+		if !ok {
+			folder = &Item{Name: folderName}
+			folders[folderName] = folder
+			collection.Item = append(collection.Item, folder)
+		}
+
+		folder.Item = append(folder.Item, buildItem(route, op, doc))
+	}
+
+	return collection
+}
+
+func routeRank(route routes.Route) int {
+	if rank, ok := firstRoutes[route.Name]; ok {
+		return rank
+	}
+	return len(firstRoutes) + 1
+}
+
+func buildItem(route routes.Route, op *openapi.Operation, doc *openapi.Document) *Item {
+	item := &Item{Name: route.Method + " " + route.Path}
+	if route.Name != "" {
+		item.Name = route.Name
+	}
+
+	request := &Request{
+		Method: route.Method,
+		Header: []Header{{Key: "Accept", Value: "application/json"}},
+		URL:    buildURL(route.Path, op, doc),
+	}
+	if !route.Auth {
+		request.Auth = &Auth{Type: "noauth"}
+	}
+
+	if op != nil {
+		if op.Summary != "" {
+			item.Name = op.Summary
+		}
+		request.Description = op.Description
+
+		if op.RequestBody != nil {
+			if media, ok := op.RequestBody.Content["application/json"]; ok {
+				body, err := json.MarshalIndent(doc.Example(media.Schema), "", "    ")
+				if err == nil {
+					raw := string(body)
+					// Only register and login use the example email, so the
+					// other requests do not fail on a duplicate
+					if _, first := firstRoutes[route.Name]; !first {
+						raw = strings.ReplaceAll(raw, `"`+openapi.ExampleEmail+`"`, `"{{$randomEmail}}"`)
+					}
+					request.Header = append(request.Header, Header{Key: "Content-Type", Value: "application/json"})
+					request.Body = &Body{Mode: "raw", Raw: raw, Options: &BodyOptions{}}
+					request.Body.Options.Raw.Language = "json"
+				}
+			}
+		}
+	}
+	item.Request = request
+
+	var exec []string
+	if status := successStatus(op); status != "" {
+		exec = append(exec, fmt.Sprintf("pm.test(\"Status is %s\", function () { pm.response.to.have.status(%s); });", status, status))
+	}
+	if tokenRoutes[route.Name] {
+		exec = append(exec, tokenScript...)
+	}
+	if len(exec) > 0 {
+		item.Event = []Event{{Listen: "test", Script: Script{Type: "text/javascript", Exec: exec}}}
+	}
+
+	return item
+}
+
+// successStatus returns the lowest 2xx response code of op from its
+// @Success comments, or "" when op documents none
+func successStatus(op *openapi.Operation) string {
+	if op == nil {
+		return ""
+	}
+
+	status := ""
+	for code := range op.Responses {
+		if len(code) == 3 && code[0] == '2' && (status == "" || code < status) {
+			status = code
+		}
+	}
+	return status
+}
+
+// buildURL turns /api/v1/products/:id into {{baseUrl}}/api/v1/products/:id
+// with an example value for the id path variable and the documented query
+// parameters disabled
+func buildURL(path string, op *openapi.Operation, doc *openapi.Document) URL {
+	url := URL{Host: []string{"{{baseUrl}}"}}
+
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		// Postman has no catch-all parameters
+		if strings.HasPrefix(segment, "*") {
+			segment = ":" + segment[1:]
+		}
+		url.Path = append(url.Path, segment)
+
+		if strings.HasPrefix(segment, ":") {
+			url.Variable = append(url.Variable, Variable{Key: segment[1:], Value: parameterExample(segment[1:], "path", op, doc)})
+		}
+	}
+
+	if op != nil {
+		for _, param := range op.Parameters {
+			if param.In != "query" {
+				continue
+			}
+			value := parameterExample(param.Name, "query", op, doc)
+			url.Query = append(url.Query, Query{Key: param.Name, Value: value, Description: param.Description, Disabled: true})
+		}
+	}
+
+	url.Raw = "{{baseUrl}}/" + strings.Join(url.Path, "/")
+	return url
+}
+
+// parameterExample returns an example value for a path or query parameter
+func parameterExample(name, in string, op *openapi.Operation, doc *openapi.Document) string {
+	if op != nil {
+		for _, param := range op.Parameters {
+			if param.Name == name && param.In == in && param.Schema != nil {
+				if value := doc.Example(param.Schema); value != nil {
+					return fmt.Sprint(value)
+				}
+			}
+		}
+	}
+	return "1"
+}
+
+// folderOf returns the first tag of op, or the first segment of path after
+// the /api/v1 prefix, e.g. "Products"
+func folderOf(path string, op *openapi.Operation) string {
+	if op != nil && len(op.Tags) > 0 {
+		return op.Tags[0]
+	}
+
+	segment := strings.Split(strings.TrimPrefix(versionPrefix.ReplaceAllString(path, ""), "/"), "/")[0]
+	if segment == "" || strings.HasPrefix(segment, ":") {
+		return "General"
+	}
+
+	runes := []rune(strings.ReplaceAll(segment, "-", " "))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,158 @@
+// Package postman reads and writes Postman collections in the v2.1 format.
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SchemaURL identifies the v2.1 collection format
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a Postman collection
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []*Item    `json:"item"`
+	Auth     *Auth      `json:"auth,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// Item is a request, or a folder when it has items of its own
+type Item struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Item        []*Item  `json:"item,omitempty"`
+	Request     *Request `json:"request,omitempty"`
+	Event       []Event  `json:"event,omitempty"`
+	// Auth of a folder, inherited by its requests
+	Auth *Auth `json:"auth,omitempty"`
+}
+
+type Request struct {
+	Method      string   `json:"method"`
+	Header      []Header `json:"header"`
+	Body        *Body    `json:"body,omitempty"`
+	URL         URL      `json:"url"`
+	Description string   `json:"description,omitempty"`
+	// Auth is nil when the request inherits the auth of its parent
+	Auth *Auth `json:"auth,omitempty"`
+}
+
+type Header struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Body struct {
+	Mode    string       `json:"mode"`
+	Raw     string       `json:"raw,omitempty"`
+	Options *BodyOptions `json:"options,omitempty"`
+	// URLEncoded holds the fields of mode "urlencoded"
+	URLEncoded []Query `json:"urlencoded,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type URL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []Query    `json:"query,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// UnmarshalJSON accepts a URL written as a plain string too
+func (u *URL) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &u.Raw)
+	}
+
+	type plain URL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+type Query struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Auth is the auth of a request, folder or collection. Type "noauth"
+// overrides an inherited auth.
+type Auth struct {
+	Type   string     `json:"type"`
+	Bearer []Variable `json:"bearer,omitempty"`
+}
+
+// Event is a script that runs before a request ("prerequest") or after its
+// response ("test")
+type Event struct {
+	Listen string `json:"listen"`
+	Script Script `json:"script"`
+}
+
+type Script struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+// Load reads a collection file. Collections exported from the Postman API,
+// which wrap the collection in a "collection" object, are accepted too.
+func Load(path string) (*Collection, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapped struct {
+		Collection *Collection `json:"collection"`
+	}
+	if err := json.Unmarshal(content, &wrapped); err != nil {
+		return nil, fmt.Errorf("invalid collection %s: %w", path, err)
+	}
+	if wrapped.Collection != nil {
+		return wrapped.Collection, nil
+	}
+
+	var collection Collection
+	if err := json.Unmarshal(content, &collection); err != nil {
+		return nil, fmt.Errorf("invalid collection %s: %w", path, err)
+	}
+	return &collection, nil
+}
+
+// Write writes the collection as indented JSON to path, creating its directory
+func (c *Collection) Write(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
@@ -0,0 +1,120 @@
+package postman
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the results as a JUnit XML report, with a test suite per
+// folder and a test case per request. Requests without checks are skipped.
+func WriteJUnit(path, name string, results []*Result) error {
+	report := junitSuites{Name: name}
+	suites := map[string]int{}
+	var durations []time.Duration
+	var total time.Duration
+
+	for _, result := range results {
+		folder := result.Folder
+		if folder == "" {
+			folder = name
+		}
+
+		index, ok := suites[folder]
+		if !ok {
+			index = len(report.Suites)
+			suites[folder] = index
+			report.Suites = append(report.Suites, junitSuite{Name: folder})
+			durations = append(durations, 0)
+		}
+		suite := &report.Suites[index]
+
+		testCase := junitCase{
+			Name:      result.Name,
+			ClassName: folder,
+			Time:      seconds(result.Duration),
+			SystemOut: fmt.Sprintf("%s %s -> %d", result.Method, result.URL, result.Status),
+		}
+		if result.Failed() {
+			testCase.Failure = &junitFailure{
+				Message: result.Failures[0],
+				Type:    "AssertionError",
+				Text:    strings.Join(result.Failures, "\n"),
+			}
+			suite.Failures++
+			report.Failures++
+		} else if result.Unchecked() {
+			testCase.Skipped = &junitSkipped{Message: "no checks"}
+			suite.Skipped++
+			report.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		report.Tests++
+		durations[index] += result.Duration
+		total += result.Duration
+	}
+
+	for i := range report.Suites {
+		report.Suites[i].Time = seconds(durations[i])
+	}
+	report.Time = seconds(total)
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(content, '\n')...), 0644)
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,316 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result is the outcome of one request of a collection run
+type Result struct {
+	// Folder is the path of the folders holding the request, e.g. "Auth"
+	Folder   string
+	Name     string
+	Method   string
+	URL      string
+	Status   int
+	Duration time.Duration
+	// Checks counts the status and equality assertions evaluated
+	Checks   int
+	Failures []string
+	// Unsupported holds the script statements that were not evaluated
+	Unsupported []string
+}
+
+// Failed reports whether an assertion of the request failed
+func (r *Result) Failed() bool {
+	return len(r.Failures) > 0
+}
+
+// Unchecked reports whether the request passed without evaluating any
+// assertion, so it only proves the response was 2xx
+func (r *Result) Unchecked() bool {
+	return !r.Failed() && r.Checks == 0
+}
+
+// Runner sends the requests of a collection to an http.Handler in order,
+// without a server
+type Runner struct {
+	Handler http.Handler
+	// Variables start with the collection variables and receive the captured values
+	Variables map[string]string
+}
+
+var templateVariable = regexp.MustCompile(`\{\{\s*(\$?[\w.-]+)\s*\}\}`)
+
+// Run sends every request of the collection and evaluates its test script
+func (r *Runner) Run(collection *Collection) []*Result {
+	if r.Variables == nil {
+		r.Variables = map[string]string{}
+	}
+	for _, variable := range collection.Variable {
+		if _, ok := r.Variables[variable.Key]; !ok {
+			r.Variables[variable.Key] = variable.Value
+		}
+	}
+
+	var results []*Result
+	var walk func(items []*Item, folder string, auth *Auth)
+	walk = func(items []*Item, folder string, auth *Auth) {
+		for _, item := range items {
+			if item.Request == nil {
+				inherited := auth
+				if item.Auth != nil {
+					inherited = item.Auth
+				}
+				walk(item.Item, joinFolder(folder, item.Name), inherited)
+				continue
+			}
+
+			requestAuth := auth
+			if item.Request.Auth != nil {
+				requestAuth = item.Request.Auth
+			}
+			results = append(results, r.send(item, folder, requestAuth))
+		}
+	}
+	walk(collection.Item, "", collection.Auth)
+
+	return results
+}
+
+func joinFolder(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + " / " + name
+}
+
+func (r *Runner) send(item *Item, folder string, auth *Auth) *Result {
+	request := item.Request
+	result := &Result{Folder: folder, Name: item.Name, Method: strings.ToUpper(request.Method)}
+	if result.Method == "" {
+		result.Method = http.MethodGet
+	}
+	result.URL = r.requestURI(request.URL)
+
+	var body io.Reader
+	contentType := ""
+	if request.Body != nil {
+		switch request.Body.Mode {
+		case "raw":
+			body = strings.NewReader(r.replace(request.Body.Raw))
+			if request.Body.Options != nil && request.Body.Options.Raw.Language == "json" {
+				contentType = "application/json"
+			}
+		case "urlencoded":
+			form := url.Values{}
+			for _, field := range request.Body.URLEncoded {
+				if !field.Disabled {
+					form.Add(r.replace(field.Key), r.replace(field.Value))
+				}
+			}
+			body = strings.NewReader(form.Encode())
+			contentType = "application/x-www-form-urlencoded"
+		default:
+			result.Unsupported = append(result.Unsupported, "body mode "+request.Body.Mode)
+		}
+	}
+
+	req := httptest.NewRequest(result.Method, result.URL, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, header := range request.Header {
+		if !header.Disabled {
+			req.Header.Set(r.replace(header.Key), r.replace(header.Value))
+		}
+	}
+	if auth != nil && auth.Type == "bearer" && req.Header.Get("Authorization") == "" {
+		for _, variable := range auth.Bearer {
+			if variable.Key == "token" {
+				if token := r.replace(variable.Value); token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+			}
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	start := time.Now()
+	r.Handler.ServeHTTP(recorder, req)
+	result.Duration = time.Since(start)
+	result.Status = recorder.Code
+
+	var document interface{}
+	decodeErr := json.Unmarshal(recorder.Body.Bytes(), &document)
+
+	statusChecked := false
+	for _, event := range item.Event {
+		if event.Listen != "test" {
+			continue
+		}
+
+		assertions, unsupported := ParseScript(event.Script.Exec)
+		result.Unsupported = append(result.Unsupported, unsupported...)
+
+		for _, assertion := range assertions {
+			if assertion.Kind == AssertStatus {
+				statusChecked = true
+			}
+			r.evaluate(result, assertion, document, decodeErr)
+		}
+	}
+
+	// Without a status test, an error response must not pass as a success
+	if !statusChecked && (result.Status < 200 || result.Status > 299) {
+		result.Failures = append(result.Failures, fmt.Sprintf("expected a 2xx status without a status test, got %d", result.Status))
+	}
+
+	return result
+}
+
+// evaluate checks an assertion against the response, or stores a captured value
+func (r *Runner) evaluate(result *Result, assertion Assertion, document interface{}, decodeErr error) {
+	prefix := ""
+	if assertion.Test != "" {
+		prefix = assertion.Test + ": "
+	}
+
+	switch assertion.Kind {
+	case AssertStatus:
+		result.Checks++
+		if result.Status != assertion.Expected {
+			result.Failures = append(result.Failures, fmt.Sprintf("%sexpected status %v, got %d", prefix, assertion.Expected, result.Status))
+		}
+
+	case AssertEqual:
+		result.Checks++
+		if decodeErr != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("%sresponse is not JSON: %s", prefix, decodeErr))
+			return
+		}
+		actual, ok := lookup(document, assertion.Path)
+		if !ok {
+			result.Failures = append(result.Failures, fmt.Sprintf("%s%s not found in the response", prefix, assertion.Path))
+			return
+		}
+		if !reflect.DeepEqual(actual, assertion.Expected) {
+			result.Failures = append(result.Failures, fmt.Sprintf("%sexpected %s to equal %s, got %s",
+				prefix, assertion.Path, encode(assertion.Expected), encode(actual)))
+		}
+
+	case AssertCapture:
+		// Scripts usually guard their captures, so a missing value is not a failure
+		if value, ok := lookup(document, assertion.Path); ok && decodeErr == nil {
+			if s, isString := value.(string); isString {
+				r.Variables[assertion.Variable] = s
+			} else {
+				r.Variables[assertion.Variable] = encode(value)
+			}
+		}
+	}
+}
+
+// requestURI resolves the variables of a URL and drops its scheme and host,
+// e.g. {{baseUrl}}/api/v1/products/:id becomes /api/v1/products/1
+func (r *Runner) requestURI(u URL) string {
+	raw := r.replace(u.Raw)
+
+	if i := strings.Index(raw, "://"); i >= 0 {
+		raw = raw[i+len("://"):]
+		if j := strings.IndexAny(raw, "/?"); j >= 0 {
+			raw = raw[j:]
+		} else {
+			raw = "/"
+		}
+	} else if j := strings.IndexAny(raw, "/?"); j > 0 {
+		// An unresolved host such as {{host}}
+		raw = raw[j:]
+	}
+
+	raw, _, _ = strings.Cut(raw, "#")
+	path, query, _ := strings.Cut(raw, "?")
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		for _, variable := range u.Variable {
+			if variable.Key == segment[1:] {
+				segments[i] = url.PathEscape(r.replace(variable.Value))
+			}
+		}
+	}
+
+	uri := escapePath(strings.Join(segments, "/"))
+	if !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri
+	}
+	if query != "" {
+		// A space would end the request line
+		uri += "?" + strings.ReplaceAll(query, " ", "%20")
+	}
+	return uri
+}
+
+// escapePath escapes what variables put in a path, such as spaces, and
+// keeps the escapes that are already there
+func escapePath(path string) string {
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return strings.ReplaceAll(path, " ", "%20")
+	}
+	return (&url.URL{Path: unescaped, RawPath: path}).EscapedPath()
+}
+
+// replace resolves {{name}} variables, leaving unknown ones as they are
+func (r *Runner) replace(s string) string {
+	return templateVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVariable.FindStringSubmatch(match)[1]
+		if value, ok := r.Variables[name]; ok {
+			return value
+		}
+		if value, ok := dynamicVariable(name); ok {
+			return value
+		}
+		return match
+	})
+}
+
+// dynamicVariable resolves the Postman dynamic variables used by exported
+// collections, e.g. {{$randomEmail}}. Every use gets a new value.
+func dynamicVariable(name string) (string, bool) {
+	switch name {
+	case "$guid":
+		b := make([]byte, 16)
+		rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+	case "$timestamp":
+		return strconv.FormatInt(time.Now().Unix(), 10), true
+	case "$randomInt":
+		return strconv.Itoa(rand.Intn(1001)), true
+	case "$randomEmail":
+		return fmt.Sprintf("user%d@example.com", rand.Intn(1000000)), true
+	}
+	return "", false
+}
+
+func encode(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
@@ -0,0 +1,54 @@
+package postman
+
+import (
+	"testing"
+)
+
+func TestRequestURI(t *testing.T) {
+	runner := &Runner{Variables: map[string]string{
+		"baseUrl":    "http://localhost:8080",
+		"product_id": "42",
+		"term":       "red shoes",
+		"slug":       "a/b",
+	}}
+
+	tests := []struct {
+		name string
+		url  URL
+		want string
+	}{
+		{"base url", URL{Raw: "{{baseUrl}}/api/v1/products"}, "/api/v1/products"},
+		{"spaces in braces", URL{Raw: "{{ baseUrl }}/health"}, "/health"},
+		{"host only", URL{Raw: "{{baseUrl}}"}, "/"},
+		{"host and query", URL{Raw: "{{baseUrl}}?page=2"}, "/?page=2"},
+		{"unresolved host", URL{Raw: "{{host}}/api/v1/products"}, "/api/v1/products"},
+		{"no host", URL{Raw: "/api/v1/products"}, "/api/v1/products"},
+		{"host without scheme", URL{Raw: "localhost:8080/api/v1/products"}, "/api/v1/products"},
+		{"variable in path", URL{Raw: "{{baseUrl}}/api/v1/products/{{product_id}}"}, "/api/v1/products/42"},
+		{
+			"path variable",
+			URL{Raw: "{{baseUrl}}/api/v1/products/:id", Variable: []Variable{{Key: "id", Value: "{{product_id}}"}}},
+			"/api/v1/products/42",
+		},
+		{
+			"path variable is escaped",
+			URL{Raw: "{{baseUrl}}/pages/:slug", Variable: []Variable{{Key: "slug", Value: "{{slug}}"}}},
+			"/pages/a%2Fb",
+		},
+		{"unknown path variable", URL{Raw: "{{baseUrl}}/api/v1/products/:id"}, "/api/v1/products/:id"},
+		{"query", URL{Raw: "{{baseUrl}}/api/v1/products?page=1&per_page=10"}, "/api/v1/products?page=1&per_page=10"},
+		{"variable in query", URL{Raw: "{{baseUrl}}/api/v1/products?search={{term}}"}, "/api/v1/products?search=red%20shoes"},
+		{"space in path", URL{Raw: "{{baseUrl}}/tags/{{term}}"}, "/tags/red%20shoes"},
+		{"escapes are kept", URL{Raw: "{{baseUrl}}/tags/red%20shoes"}, "/tags/red%20shoes"},
+		{"fragment", URL{Raw: "{{baseUrl}}/docs/index.html#/products"}, "/docs/index.html"},
+		{"unknown variable in query", URL{Raw: "/products?page={{page}}"}, "/products?page={{page}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runner.requestURI(tt.url); got != tt.want {
+				t.Errorf("requestURI(%q) = %q, want %q", tt.url.Raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of assertions
+const (
+	// AssertStatus checks the status code of the response
+	AssertStatus = "status"
+	// AssertEqual checks the value at a JSON path of the response body
+	AssertEqual = "equal"
+	// AssertCapture stores the value at a JSON path in a variable
+	AssertCapture = "capture"
+)
+
+// Assertion is a statement of a test script that can be evaluated without
+// a JavaScript runtime
+type Assertion struct {
+	Kind string
+	// Test is the name of the enclosing pm.test, if any
+	Test string
+	// Path is the JSON path of AssertEqual and AssertCapture, e.g. "data.token"
+	Path     string
+	Expected interface{}
+	Variable string
+}
+
+var (
+	// const body = pm.response.json();
+	jsonVariable = regexp.MustCompile(`(?:const|let|var)\s+(\w+)\s*=\s*pm\.response\.json\(\)`)
+
+	// pm.test("Status is 200", function () {
+	testName = regexp.MustCompile(`pm\.test\(\s*["'` + "`" + `]([^"'` + "`" + `]*)["'` + "`" + `]`)
+
+	// pm.response.to.have.status(200) and pm.expect(pm.response.code).to.eql(200)
+	statusAssertion = regexp.MustCompile(`pm\.response\.to\.have\.status\(\s*(\d+)\s*\)|` +
+		`pm\.expect\(\s*pm\.response\.(?:code|status)\s*\)\.to\.(?:eql|equal|be\.equal)\(\s*(\d+)\s*\)`)
+
+	// pm.expect(body.data.name).to.eql("Widget")
+	equalAssertion = regexp.MustCompile(`pm\.expect\(\s*([\w.\[\]"'()]+?)\s*\)\.to\.(?:eql|equal|deep\.equal|be\.equal)\(\s*(.+?)\s*\)\s*;?\s*(?:\n|$|\}|\))`)
+
+	// pm.expect(body.success).to.be.true
+	booleanAssertion = regexp.MustCompile(`pm\.expect\(\s*([\w.\[\]"'()]+?)\s*\)\.to\.be\.(true|false|null)\b`)
+
+	// pm.environment.set("token", body.data.token)
+	captureStatement = regexp.MustCompile(`pm\.(?:environment|collectionVariables|globals|variables)\.set\(\s*["'](\w+)["']\s*,\s*([\w.\[\]"'()]+?)\s*\)`)
+
+	// Any other assertion is reported as unsupported
+	anyAssertion = regexp.MustCompile(`pm\.expect\(|pm\.response\.to\.`)
+
+	// body["data"] is body.data
+	bracketProperty = regexp.MustCompile(`\[["'](\w+)["']\]`)
+)
+
+// ParseScript extracts the status, JSON path equality and variable capture
+// statements of a test script. Statements it cannot evaluate are returned
+// as unsupported.
+func ParseScript(lines []string) (assertions []Assertion, unsupported []string) {
+	script := strings.Join(lines, "\n")
+
+	roots := map[string]bool{}
+	for _, match := range jsonVariable.FindAllStringSubmatch(script, -1) {
+		roots[match[1]] = true
+	}
+
+	tests := testName.FindAllStringSubmatchIndex(script, -1)
+	testAt := func(offset int) string {
+		name := ""
+		for _, test := range tests {
+			if test[0] > offset {
+				break
+			}
+			name = script[test[2]:test[3]]
+		}
+		return name
+	}
+
+	type found struct {
+		offset    int
+		assertion Assertion
+	}
+	var all []found
+	handled := map[int]bool{}
+
+	for _, match := range statusAssertion.FindAllStringSubmatchIndex(script, -1) {
+		code := submatch(script, match, 1)
+		if code == "" {
+			code = submatch(script, match, 2)
+		}
+		status, _ := strconv.Atoi(code)
+		all = append(all, found{match[0], Assertion{Kind: AssertStatus, Expected: status}})
+		handled[match[0]] = true
+	}
+
+	for _, match := range equalAssertion.FindAllStringSubmatchIndex(script, -1) {
+		if handled[match[0]] {
+			continue
+		}
+		path, ok := jsonPath(submatch(script, match, 1), roots)
+		if !ok {
+			continue
+		}
+		expected, err := parseLiteral(submatch(script, match, 2))
+		if err != nil {
+			continue
+		}
+		all = append(all, found{match[0], Assertion{Kind: AssertEqual, Path: path, Expected: expected}})
+		handled[match[0]] = true
+	}
+
+	for _, match := range booleanAssertion.FindAllStringSubmatchIndex(script, -1) {
+		path, ok := jsonPath(submatch(script, match, 1), roots)
+		if !ok {
+			continue
+		}
+		expected, _ := parseLiteral(submatch(script, match, 2))
+		all = append(all, found{match[0], Assertion{Kind: AssertEqual, Path: path, Expected: expected}})
+		handled[match[0]] = true
+	}
+
+	for _, match := range captureStatement.FindAllStringSubmatchIndex(script, -1) {
+		path, ok := jsonPath(submatch(script, match, 2), roots)
+		if !ok {
+			unsupported = append(unsupported, lineAt(script, match[0]))
+			continue
+		}
+		all = append(all, found{match[0], Assertion{Kind: AssertCapture, Path: path, Variable: submatch(script, match, 1)}})
+	}
+
+	for _, match := range anyAssertion.FindAllStringIndex(script, -1) {
+		if !handled[match[0]] {
+			unsupported = append(unsupported, lineAt(script, match[0]))
+		}
+	}
+
+	// Keep the order of the script
+	sort.SliceStable(all, func(i, j int) bool { return all[i].offset < all[j].offset })
+	for _, f := range all {
+		f.assertion.Test = testAt(f.offset)
+		assertions = append(assertions, f.assertion)
+	}
+
+	return assertions, unsupported
+}
+
+// lineAt returns the rest of the line of script starting at offset
+func lineAt(script string, offset int) string {
+	line := script[offset:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	return strings.TrimSpace(line)
+}
+
+func submatch(s string, match []int, group int) string {
+	if match[2*group] < 0 {
+		return ""
+	}
+	return s[match[2*group]:match[2*group+1]]
+}
+
+// jsonPath turns body.data.items[0].id into data.items[0].id when body holds
+// pm.response.json()
+func jsonPath(expr string, roots map[string]bool) (string, bool) {
+	const inline = "pm.response.json()"
+
+	var rest string
+	switch {
+	case strings.HasPrefix(expr, inline):
+		rest = strings.TrimPrefix(expr, inline)
+	default:
+		root := expr
+		if i := strings.IndexAny(expr, ".["); i >= 0 {
+			root = expr[:i]
+		}
+		if !roots[root] {
+			return "", false
+		}
+		rest = expr[len(root):]
+	}
+
+	rest = bracketProperty.ReplaceAllString(rest, ".$1")
+	return strings.TrimPrefix(rest, "."), true
+}
+
+// parseLiteral parses a JavaScript literal such as 'Widget', 42 or true
+func parseLiteral(literal string) (interface{}, error) {
+	if len(literal) >= 2 && literal[0] == '\'' && literal[len(literal)-1] == '\'' {
+		literal = strconv.Quote(literal[1 : len(literal)-1])
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(literal), &value); err != nil {
+		return nil, fmt.Errorf("unsupported literal %s", literal)
+	}
+	return value, nil
+}
+
+// lookup returns the value at path in a decoded JSON document, e.g.
+// "data.items[0].id"
+func lookup(document interface{}, path string) (interface{}, bool) {
+	value := document
+	if path == "" {
+		return value, true
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		var indexes []int
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			name = part[:i]
+			for _, index := range strings.Split(strings.TrimSuffix(part[i+1:], "]"), "][") {
+				n, err := strconv.Atoi(index)
+				if err != nil {
+					return nil, false
+				}
+				indexes = append(indexes, n)
+			}
+		}
+
+		if name != "" {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object[name]; !ok {
+				return nil, false
+			}
+		}
+
+		for _, index := range indexes {
+			array, ok := value.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, false
+			}
+			value = array[index]
+		}
+	}
+
+	return value, true
+}
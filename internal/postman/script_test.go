@@ -0,0 +1,146 @@
+package postman
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	tests := []struct {
+		name            string
+		script          string
+		want            []Assertion
+		wantUnsupported []string
+	}{
+		{name: "empty", script: ""},
+		{
+			name:   "status",
+			script: `pm.test("Status is 200", function () { pm.response.to.have.status(200); });`,
+			want:   []Assertion{{Kind: AssertStatus, Test: "Status is 200", Expected: 200}},
+		},
+		{
+			name:   "status through expect",
+			script: "pm.expect(pm.response.code).to.eql(201);",
+			want:   []Assertion{{Kind: AssertStatus, Expected: 201}},
+		},
+		{
+			name: "equal",
+			script: `const body = pm.response.json();
+pm.test("Returns the product", function () {
+    pm.expect(body.data.name).to.eql("Widget");
+    pm.expect(body["data"]["price"]).to.equal(9.5);
+    pm.expect(body.data.tags[0]).to.eql('new');
+});`,
+			want: []Assertion{
+				{Kind: AssertEqual, Test: "Returns the product", Path: "data.name", Expected: "Widget"},
+				{Kind: AssertEqual, Test: "Returns the product", Path: "data.price", Expected: 9.5},
+				{Kind: AssertEqual, Test: "Returns the product", Path: "data.tags[0]", Expected: "new"},
+			},
+		},
+		{
+			name:   "inline json",
+			script: "pm.expect(pm.response.json().success).to.be.true;",
+			want:   []Assertion{{Kind: AssertEqual, Path: "success", Expected: true}},
+		},
+		{
+			name:   "boolean and null",
+			script: "let json = pm.response.json();\npm.expect(json.success).to.be.false;\npm.expect(json.data).to.be.null;",
+			want: []Assertion{
+				{Kind: AssertEqual, Path: "success", Expected: false},
+				{Kind: AssertEqual, Path: "data", Expected: nil},
+			},
+		},
+		{
+			name: "capture",
+			script: `var body = pm.response.json();
+pm.environment.set("token", body.data.token);
+pm.collectionVariables.set('user_id', body.data.user.id);`,
+			want: []Assertion{
+				{Kind: AssertCapture, Path: "data.token", Variable: "token"},
+				{Kind: AssertCapture, Path: "data.user.id", Variable: "user_id"},
+			},
+		},
+		{
+			name: "in script order under their test",
+			script: `const body = pm.response.json();
+pm.test("Created", function () {
+    pm.response.to.have.status(201);
+});
+pm.test("Stores the id", function () {
+    pm.expect(body.success).to.be.true;
+    pm.environment.set("product_id", body.data.id);
+});`,
+			want: []Assertion{
+				{Kind: AssertStatus, Test: "Created", Expected: 201},
+				{Kind: AssertEqual, Test: "Stores the id", Path: "success", Expected: true},
+				{Kind: AssertCapture, Test: "Stores the id", Path: "data.id", Variable: "product_id"},
+			},
+		},
+		{
+			name:            "unknown root",
+			script:          "pm.expect(other.value).to.eql(1);",
+			wantUnsupported: []string{"pm.expect(other.value).to.eql(1);"},
+		},
+		{
+			name:            "unsupported matcher",
+			script:          "const body = pm.response.json();\npm.expect(body.data).to.have.property('id');",
+			wantUnsupported: []string{"pm.expect(body.data).to.have.property('id');"},
+		},
+		{
+			name:            "unsupported literal",
+			script:          "const body = pm.response.json();\npm.expect(body.total).to.eql(items.length);",
+			wantUnsupported: []string{"pm.expect(body.total).to.eql(items.length);"},
+		},
+		{
+			name:            "unsupported capture",
+			script:          `pm.environment.set("now", Date.now())`,
+			wantUnsupported: []string{`pm.environment.set("now", Date.now())`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions, unsupported := ParseScript(strings.Split(tt.script, "\n"))
+			if !reflect.DeepEqual(assertions, tt.want) {
+				t.Errorf("assertions =\n%+v\nwant\n%+v", assertions, tt.want)
+			}
+			if !reflect.DeepEqual(unsupported, tt.wantUnsupported) {
+				t.Errorf("unsupported = %q, want %q", unsupported, tt.wantUnsupported)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	document := map[string]interface{}{
+		"data": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": 1.0},
+				map[string]interface{}{"id": 2.0, "tags": []interface{}{"a", "b"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		path   string
+		want   interface{}
+		wantOK bool
+	}{
+		{"", document, true},
+		{"data.items[1].id", 2.0, true},
+		{"data.items[1].tags[1]", "b", true},
+		{"data.items[2].id", nil, false},
+		{"data.items[-1]", nil, false},
+		{"data.items[x]", nil, false},
+		{"data.missing", nil, false},
+		{"data.items.id", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := lookup(document, tt.path)
+		if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookup(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package scaffold creates new projects from the skeleton embedded in the
+// gomen CLI.
+package scaffold
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+//go:generate go run ./sync
+
+// The skeleton is committed. Naming main.go.tmpl makes the build fail when it
+// is missing instead of `gomen new` failing at runtime.
+//
+//go:embed all:skeleton skeleton/main.go.tmpl
+var skeleton embed.FS
+
+// Module is the module path the generator templates are written with
+const Module = "gomen"
+
+// Drivers are the database drivers a project can start with
+var Drivers = []string{"mysql", "postgres", "sqlite"}
+
+// Options describe the project to create
+type Options struct {
+	// Name is the project directory
+	Name string
+	// Module is the Go module path, e.g. github.com/acme/myapi
+	Module string
+	// Driver is the database driver written to .env
+	Driver string
+}
+
+var (
+	modulePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~/-]*$`)
+	moduleLine    = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+)
+
+// New creates the project described by opts in the current directory
+func New(opts Options) ([]string, error) {
+	if !modulePattern.MatchString(opts.Module) || strings.Contains(opts.Module, "//") || strings.HasSuffix(opts.Module, "/") {
+		return nil, fmt.Errorf("invalid module path %q", opts.Module)
+	}
+	if !isDriver(opts.Driver) {
+		return nil, fmt.Errorf("unknown database driver %q, use one of: %s", opts.Driver, strings.Join(Drivers, ", "))
+	}
+
+	if entries, err := os.ReadDir(opts.Name); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", opts.Name)
+	}
+
+	goMod, err := skeleton.ReadFile("skeleton/go.mod.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	from := Module
+	if matches := moduleLine.FindSubmatch(goMod); matches != nil {
+		from = string(matches[1])
+	}
+
+	var created []string
+	err = fs.WalkDir(skeleton, "skeleton", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(name, ".tmpl") {
+			return err
+		}
+
+		content, err := skeleton.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		relative := strings.TrimSuffix(strings.TrimPrefix(name, "skeleton/"), ".tmpl")
+		content = rewrite(relative, content, from, opts.Module)
+
+		if err := writeFile(opts.Name, relative, content); err != nil {
+			return err
+		}
+		created = append(created, relative)
+
+		// The project embeds its own skeleton, fill it in as go generate
+		// would so the project builds without running it
+		return writeFile(opts.Name, path.Join("internal", "scaffold", "skeleton", relative+".tmpl"), content)
+	})
+	if err != nil {
+		return created, err
+	}
+
+	// A ready to use .env for the chosen driver
+	example, err := skeleton.ReadFile("skeleton/.env.example.tmpl")
+	if err != nil {
+		return created, err
+	}
+	if err := writeFile(opts.Name, ".env", env(example, opts)); err != nil {
+		return created, err
+	}
+	created = append(created, ".env")
+
+	if err := writeFile(opts.Name, "README.md", []byte(readme(opts))); err != nil {
+		return created, err
+	}
+	if err := writeFile(opts.Name, ".gitignore", []byte(gitignore)); err != nil {
+		return created, err
+	}
+	created = append(created, "README.md", ".gitignore")
+
+	return created, nil
+}
+
+// rewrite moves a skeleton file from module from to module to
+func rewrite(name string, content []byte, from, to string) []byte {
+	switch {
+	case name == "go.mod":
+		return moduleLine.ReplaceAll(content, []byte("module "+to))
+	case path.Ext(name) == ".go":
+		return []byte(RewriteImports(string(content), from, to))
+	}
+	return content
+}
+
+// RewriteImports replaces import paths under module from with the same
+// packages under module to
+func RewriteImports(source, from, to string) string {
+	if from == to {
+		return source
+	}
+	return strings.ReplaceAll(source, `"`+from+`/`, `"`+to+`/`)
+}
+
+// env fills .env.example in for the project and database driver
+func env(example []byte, opts Options) []byte {
+	base := path.Base(opts.Name)
+	values := map[string]string{
+		"APP_NAME":    fmt.Sprintf("%q", base),
+		"DB_DRIVER":   opts.Driver,
+		"DB_DATABASE": strings.ReplaceAll(base, "-", "_"),
+		"JWT_SECRET":  secret(),
+	}
+
+	switch opts.Driver {
+	case "postgres":
+		values["DB_PORT"] = "5432"
+		values["DB_USERNAME"] = "postgres"
+	case "mysql":
+		values["DB_PORT"] = "3306"
+		values["DB_USERNAME"] = "root"
+	case "sqlite":
+		values["DB_DATABASE"] += ".db"
+	}
+
+	lines := strings.Split(string(example), "\n")
+	for i, line := range lines {
+		key := strings.SplitN(line, "=", 2)[0]
+		if value, ok := values[key]; ok && !strings.HasPrefix(line, "#") {
+			lines[i] = key + "=" + value
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// secret returns a random JWT secret
+func secret() string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "your-super-secret-key-change-this-in-production"
+	}
+	return hex.EncodeToString(key)
+}
+
+const gitignore = `.env
+/bin/
+*.db
+database/schema/*.sql
+`
+
+func readme(opts Options) string {
+	return fmt.Sprintf("# %s\n\nREST API built with GoMen.\n\n"+
+		"```bash\n"+
+		"gomen migrate       # Create tables (%s, see .env)\n"+
+		"gomen seed          # Seed the admin user\n"+
+		"gomen serve         # Start the server on port 8080\n"+
+		"```\n", path.Base(opts.Name), opts.Driver)
+}
+
+func writeFile(dir, name string, content []byte) error {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, content, 0644)
+}
+
+func isDriver(driver string) bool {
+	for _, d := range Drivers {
+		if d == driver {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// Command sync copies the project files into internal/scaffold/skeleton,
+// where the gomen CLI embeds them for `gomen new`. Every file gets a .tmpl
+// suffix so the Go files are not compiled as part of this module. Only files
+// tracked by git are copied, so local files never end up in the skeleton.
+// The skeleton is committed, run this after changing the project files.
+//
+// It is run by go generate from internal/scaffold.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// root is the repository root, relative to internal/scaffold
+const root = "../.."
+
+const skeleton = "skeleton"
+
+// dirs are copied with their Go files and generator stubs
+var dirs = []string{"app", "cmd", "config", "database", "helpers", "internal", "routes"}
+
+// files are copied as they are
+var files = []string{
+	"main.go", "go.mod", "go.sum", ".env.example", "Makefile",
+	filepath.Join("internal", "scaffold", skeleton, "README.md"),
+}
+
+func main() {
+	if err := clean(); err != nil {
+		log.Fatal(err)
+	}
+
+	tracked, err := trackedFiles()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count := 0
+	for _, file := range files {
+		if err := copyFile(file); err != nil {
+			log.Fatal(err)
+		}
+		count++
+	}
+
+	for _, relative := range tracked {
+		if !copied(relative) {
+			continue
+		}
+		if err := copyFile(relative); err != nil {
+			// Deleted but not yet committed
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			log.Fatal(err)
+		}
+		count++
+	}
+
+	log.Printf("Copied %d files into %s", count, skeleton)
+}
+
+// trackedFiles lists the files git tracks in dirs, relative to root
+func trackedFiles() ([]string, error) {
+	cmd := exec.Command("git", append([]string{"ls-files", "-z", "--"}, dirs...)...)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var tracked []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			tracked = append(tracked, filepath.FromSlash(name))
+		}
+	}
+	return tracked, nil
+}
+
+// copied reports whether a tracked file belongs in the skeleton: Go files
+// and generator stubs, but no tests and never the skeleton itself
+func copied(relative string) bool {
+	if strings.HasPrefix(relative, filepath.Join("internal", "scaffold", skeleton)+string(filepath.Separator)) {
+		return false
+	}
+	if strings.HasSuffix(relative, "_test.go") {
+		return false
+	}
+	ext := filepath.Ext(relative)
+	return ext == ".go" || ext == ".stub"
+}
+
+// clean removes the previous skeleton, keeping its README
+func clean() error {
+	entries, err := os.ReadDir(skeleton)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == "README.md" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(skeleton, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(relative string) error {
+	content, err := os.ReadFile(filepath.Join(root, relative))
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(skeleton, relative+".tmpl")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, content, 0644)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine) {
+func SetupRoutes(router *Group) {
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		responses.Success(c, "OK", gin.H{
@@ -25,7 +25,7 @@ func SetupRoutes(router *gin.Engine) {
 	}
 }
 
-func setupProductRoutes(rg *gin.RouterGroup) {
+func setupProductRoutes(rg *Group) {
 	productController := controllers.NewProductController()
 
 	products := rg.Group("/products")
@@ -39,7 +39,7 @@ func setupProductRoutes(rg *gin.RouterGroup) {
 	}
 }
 
-func setupAuthRoutes(rg *gin.RouterGroup) {
+func setupAuthRoutes(rg *Group) {
 	authController := controllers.NewAuthController()
 
 	auth := rg.Group("/auth")
@@ -60,7 +60,7 @@ func setupAuthRoutes(rg *gin.RouterGroup) {
 	}
 }
 
-func setupUserRoutes(rg *gin.RouterGroup) {
+func setupUserRoutes(rg *Group) {
 	userController := controllers.NewUserController()
 
 	users := rg.Group("/users")
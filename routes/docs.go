@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// OpenAPIFile is the document written by gomen openapi:generate
+var OpenAPIFile = filepath.Join("docs", "openapi.json")
+
+// swaggerInitializer points the embedded Swagger UI at the generated document
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// setupDocsRoutes serves the OpenAPI document at /docs/openapi.json and
+// Swagger UI at /docs
+func setupDocsRoutes(router *Group) {
+	router.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/docs/index.html")
+	})
+	router.GET("/docs/*file", serveDocs)
+}
+
+func serveDocs(c *gin.Context) {
+	file := c.Param("file")
+
+	switch file {
+	case "/":
+		c.Redirect(http.StatusMovedPermanently, "/docs/index.html")
+		return
+
+	case "/openapi.json":
+		if _, err := os.Stat(OpenAPIFile); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "OpenAPI document not found, run: gomen openapi:generate",
+			})
+			return
+		}
+		c.Header("Cache-Control", "no-cache")
+		c.File(OpenAPIFile)
+		return
+
+	case "/swagger-initializer.js":
+		c.Data(http.StatusOK, "application/javascript; charset=utf-8", []byte(swaggerInitializer))
+		return
+	}
+
+	content, err := swaggerFiles.ReadFile(path.Clean(file))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(file))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Data(http.StatusOK, contentType, content)
+}
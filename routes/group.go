@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Group is a gin.RouterGroup that records the handler chain of every route
+// registered through it. gin only reports the last handler of a route, so
+// List reads the middleware of each route from these records.
+type Group struct {
+	*gin.RouterGroup
+	chains map[string]gin.HandlersChain
+}
+
+var (
+	chainsMu sync.Mutex
+	// chains maps each engine to the "METHOD path" handler chains of its routes
+	chains = map[*gin.Engine]map[string]gin.HandlersChain{}
+)
+
+// anyMethods are the methods Any registers, the same list gin uses
+var anyMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodHead, http.MethodOptions, http.MethodDelete, http.MethodConnect,
+	http.MethodTrace,
+}
+
+// NewGroup returns the root group of engine
+func NewGroup(engine *gin.Engine) *Group {
+	chainsMu.Lock()
+	defer chainsMu.Unlock()
+
+	if chains[engine] == nil {
+		chains[engine] = map[string]gin.HandlersChain{}
+	}
+	return &Group{RouterGroup: &engine.RouterGroup, chains: chains[engine]}
+}
+
+// Group creates a sub group with the given path prefix and middlewares
+func (g *Group) Group(relativePath string, handlers ...gin.HandlerFunc) *Group {
+	return &Group{RouterGroup: g.RouterGroup.Group(relativePath, handlers...), chains: g.chains}
+}
+
+// Use adds middlewares to the routes registered on the group afterwards
+func (g *Group) Use(middleware ...gin.HandlerFunc) gin.IRoutes {
+	g.RouterGroup.Use(middleware...)
+	return g
+}
+
+// Handle registers a route and records its handler chain
+func (g *Group) Handle(method, relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	g.RouterGroup.Handle(method, relativePath, handlers...)
+
+	chain := append(gin.HandlersChain{}, g.Handlers...)
+	g.chains[method+" "+joinPaths(g.BasePath(), relativePath)] = append(chain, handlers...)
+	return g
+}
+
+func (g *Group) GET(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (g *Group) POST(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (g *Group) PUT(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+func (g *Group) PATCH(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+func (g *Group) DELETE(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+func (g *Group) OPTIONS(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodOptions, relativePath, handlers...)
+}
+
+func (g *Group) HEAD(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodHead, relativePath, handlers...)
+}
+
+func (g *Group) Any(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	for _, method := range anyMethods {
+		g.Handle(method, relativePath, handlers...)
+	}
+	return g
+}
+
+func (g *Group) Match(methods []string, relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	for _, method := range methods {
+		g.Handle(method, relativePath, handlers...)
+	}
+	return g
+}
+
+// recordedChains returns the handler chains recorded for engine's routes
+func recordedChains(engine *gin.Engine) map[string]gin.HandlersChain {
+	chainsMu.Lock()
+	defer chainsMu.Unlock()
+	return chains[engine]
+}
+
+// joinPaths builds the full path of a route the way gin does
+func joinPaths(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+	joined := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
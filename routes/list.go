@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"fmt"
+	"gomen/app/middlewares"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route describes a registered route for route:list and the API exporters
+type Route struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware"`
+	// Auth is true when AuthMiddleware guards the route
+	Auth bool `json:"auth"`
+}
+
+// authMiddleware is the function name prefix of the handlers returned by AuthMiddleware
+var authMiddleware = funcName(reflect.ValueOf(middlewares.AuthMiddleware).Pointer()) + "."
+
+// methodOrder sorts the routes of one path
+var methodOrder = map[string]int{"GET": 1, "HEAD": 2, "POST": 3, "PUT": 4, "PATCH": 5, "DELETE": 6, "OPTIONS": 7}
+
+// List returns the routes registered on engine, sorted by path. Every route
+// must be registered through a Group, otherwise its middleware is unknown.
+func List(engine *gin.Engine) ([]Route, error) {
+	chains := recordedChains(engine)
+
+	var routes []Route
+	for _, info := range engine.Routes() {
+		chain, ok := chains[info.Method+" "+info.Path]
+		if !ok {
+			return nil, fmt.Errorf("route %s %s was not registered through routes.Group, its middleware is unknown", info.Method, info.Path)
+		}
+
+		route := Route{
+			Method:     info.Method,
+			Path:       info.Path,
+			Handler:    shortName(info.Handler),
+			Middleware: []string{},
+		}
+		route.Name = routeName(route.Handler)
+
+		// The last handler is the route handler
+		for _, handler := range chain[:len(chain)-1] {
+			name := funcName(reflect.ValueOf(handler).Pointer())
+			if strings.HasPrefix(name, authMiddleware) {
+				route.Auth = true
+			}
+			route.Middleware = append(route.Middleware, middlewareName(name))
+		}
+
+		routes = append(routes, route)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return methodOrder[routes[i].Method] < methodOrder[routes[j].Method]
+	})
+
+	return routes, nil
+}
+
+func funcName(pc uintptr) string {
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// shortName turns "gomen/app/controllers.(*ProductController).Index-fm"
+// into "ProductController.Index"
+func shortName(name string) string {
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+
+	if i := strings.Index(name, "."); i >= 0 && strings.Contains(name[i+1:], "Controller.") {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// middlewareName turns "gomen/app/middlewares.AuthMiddleware.func1" into
+// "AuthMiddleware" and "github.com/gin-contrib/cors.New.func1" into "cors.New"
+func middlewareName(name string) string {
+	name = shortName(name)
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.TrimPrefix(name, "middlewares.")
+}
+
+// routeName derives a name from a controller handler: ProductController.Index
+// becomes "product.index". Closures have no name.
+func routeName(handler string) string {
+	parts := strings.Split(handler, ".")
+	if len(parts) != 2 || !strings.HasSuffix(parts[0], "Controller") {
+		return ""
+	}
+	return snakeCase(strings.TrimSuffix(parts[0], "Controller")) + "." + snakeCase(parts[1])
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"gomen/app/middlewares"
+	"gomen/config"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewRouter returns the gin engine with the global middlewares and all routes
+func NewRouter() *gin.Engine {
+	if config.Get().App.Env == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+
+	// Global middlewares
+	router.Use(middlewares.RecoveryMiddleware())
+	router.Use(middlewares.LoggerMiddleware())
+	router.Use(middlewares.CorsMiddleware())
+	router.Use(middlewares.RateLimitMiddleware(100, time.Minute)) // 100 requests per minute
+
+	// Setup routes through a Group so route:list knows their middleware
+	root := NewGroup(router)
+	SetupRoutes(root)
+
+	// API documentation, only when enabled explicitly
+	if config.Get().App.Docs {
+		setupDocsRoutes(root)
+	}
+
+	return router
+}